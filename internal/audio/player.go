@@ -14,9 +14,10 @@ type Player struct {
 	filename  string
 	isPlaying bool
 	isPaused  bool
+	muted     bool
 	stopOnce  sync.Once
 	volume    float64
-	position  time.Duration
+	position  time.Duration // Where the running ffplay started, or where playback was paused
 	duration  time.Duration
 	ffplayCmd *exec.Cmd
 	stopChan  chan struct{}
@@ -100,52 +101,92 @@ func (p *Player) Play() error {
 	p.stopChan = make(chan struct{})
 	p.stopOnce = sync.Once{}
 
+	if err := p.startLocked(); err != nil {
+		return err
+	}
+
+	p.isPlaying = true
+	p.isPaused = false
+	return nil
+}
+
+// startLocked starts ffplay from the current position at the current volume
+// FFplay can't pause, seek or change volume once running, so every such change
+// restarts it. p.mutex must be held.
+func (p *Player) startLocked() error {
 	// FFplay volume is 0-100, convert from our 0.0-1.0 scale
 	volumeInt := int(p.volume * 100)
+	if p.muted {
+		volumeInt = 0
+	}
 
-	// Start ffplay for audio playback (no video display)
-	p.ffplayCmd = exec.Command("ffplay",
+	args := []string{
 		"-nodisp",   // No video display
 		"-autoexit", // Exit when playback ends
 		"-loglevel", "quiet",
 		"-volume", fmt.Sprintf("%d", volumeInt),
-		"-i", p.filename,
-	)
+	}
+	if p.position > 0 {
+		args = append(args, "-ss", fmt.Sprintf("%.3f", p.position.Seconds()))
+	}
+	args = append(args, "-i", p.filename)
+
+	// Start ffplay for audio playback (no video display)
+	cmd := exec.Command("ffplay", args...)
 
 	// Don't inherit stdin to avoid terminal issues
-	p.ffplayCmd.Stdin = nil
-	p.ffplayCmd.Stdout = nil
-	p.ffplayCmd.Stderr = nil
+	cmd.Stdin = nil
+	cmd.Stdout = nil
+	cmd.Stderr = nil
 
-	if err := p.ffplayCmd.Start(); err != nil {
+	if err := cmd.Start(); err != nil {
 		return fmt.Errorf("failed to start ffplay: %w", err)
 	}
 
+	p.ffplayCmd = cmd
 	p.startTime = time.Now()
-	p.isPlaying = true
-	p.isPaused = false
 
 	// Monitor playback in background
-	go p.monitorPlayback()
+	go p.monitorPlayback(cmd)
 
 	return nil
 }
 
-// monitorPlayback tracks playback position and handles completion
-func (p *Player) monitorPlayback() {
-	defer func() {
-		p.mutex.Lock()
-		p.isPlaying = false
-		p.mutex.Unlock()
-	}()
+// stopLocked kills the running ffplay, if any; monitorPlayback reaps it
+// p.mutex must be held.
+func (p *Player) stopLocked() {
+	if p.ffplayCmd != nil && p.ffplayCmd.Process != nil {
+		p.ffplayCmd.Process.Kill()
+	}
+	p.ffplayCmd = nil
+}
+
+// restartLocked restarts a playing ffplay from the current position so a
+// changed position or volume takes effect; p.mutex must be held
+func (p *Player) restartLocked() error {
+	if !p.isPlaying || p.isPaused {
+		return nil
+	}
+
+	p.position += time.Since(p.startTime)
+	p.stopLocked()
+	return p.startLocked()
+}
+
+// monitorPlayback waits for ffplay to exit and marks playback as finished,
+// unless the process was replaced or stopped meanwhile
+func (p *Player) monitorPlayback(cmd *exec.Cmd) {
+	cmd.Wait()
 
-	// Wait for ffplay to complete
-	if p.ffplayCmd != nil {
-		p.ffplayCmd.Wait()
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.ffplayCmd == cmd {
+		p.ffplayCmd = nil
+		p.isPlaying = false
 	}
 }
 
-// Pause pauses audio playback (not fully supported with ffplay, stops instead)
+// Pause pauses audio playback, stopping ffplay and remembering the position
 func (p *Player) Pause() error {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
@@ -153,15 +194,18 @@ func (p *Player) Pause() error {
 	if !p.isPlaying {
 		return fmt.Errorf("audio is not playing")
 	}
+	if p.isPaused {
+		return nil
+	}
 
-	// FFplay doesn't support true pause, so we stop and record position
-	p.position = time.Since(p.startTime)
+	p.position += time.Since(p.startTime)
+	p.stopLocked()
 	p.isPaused = true
 
 	return nil
 }
 
-// Resume resumes paused audio playback
+// Resume resumes paused audio playback from where it was paused
 func (p *Player) Resume() error {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
@@ -170,6 +214,9 @@ func (p *Player) Resume() error {
 		return fmt.Errorf("audio is not paused")
 	}
 
+	if err := p.startLocked(); err != nil {
+		return err
+	}
 	p.isPaused = false
 	return nil
 }
@@ -187,31 +234,32 @@ func (p *Player) Stop() error {
 		close(p.stopChan)
 	})
 
-	if p.ffplayCmd != nil && p.ffplayCmd.Process != nil {
-		p.ffplayCmd.Process.Kill()
-		p.ffplayCmd.Wait()
-	}
-
+	p.stopLocked()
 	p.isPlaying = false
 	p.isPaused = false
 	p.position = 0
-	p.ffplayCmd = nil
 
 	return nil
 }
 
 // SetVolume sets the playback volume (0.0 to 1.0)
-// Note: Volume change takes effect on next Play() call
+// Note: ffplay is restarted for the change to take effect, leaving a short gap
 func (p *Player) SetVolume(volume float64) error {
 	if volume < 0.0 || volume > 1.0 {
 		return fmt.Errorf("volume must be between 0.0 and 1.0")
 	}
 
 	p.mutex.Lock()
-	p.volume = volume
-	p.mutex.Unlock()
+	defer p.mutex.Unlock()
 
-	return nil
+	if volume == p.volume {
+		return nil
+	}
+	p.volume = volume
+	if p.muted {
+		return nil
+	}
+	return p.restartLocked()
 }
 
 // GetVolume returns the current volume
@@ -221,18 +269,41 @@ func (p *Player) GetVolume() float64 {
 	return p.volume
 }
 
-// Seek moves to a specific position in the audio
-// Note: Seek is not fully supported with ffplay, position is tracked only
+// SetMuted silences or restores playback without changing the volume
+func (p *Player) SetMuted(muted bool) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if muted == p.muted {
+		return nil
+	}
+	p.muted = muted
+	return p.restartLocked()
+}
+
+// IsMuted returns true if playback is muted
+func (p *Player) IsMuted() bool {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.muted
+}
+
+// Seek moves to a specific position in the audio, restarting ffplay there if
+// it is playing
 func (p *Player) Seek(position time.Duration) error {
 	if position < 0 || (p.duration > 0 && position > p.duration) {
 		return fmt.Errorf("seek position out of range")
 	}
 
 	p.mutex.Lock()
-	p.position = position
-	p.mutex.Unlock()
+	defer p.mutex.Unlock()
 
-	return nil
+	p.position = position
+	if !p.isPlaying || p.isPaused {
+		return nil
+	}
+	p.stopLocked()
+	return p.startLocked()
 }
 
 // GetPosition returns the current playback position
@@ -241,7 +312,7 @@ func (p *Player) GetPosition() time.Duration {
 	defer p.mutex.RUnlock()
 
 	if p.isPlaying && !p.isPaused {
-		return time.Since(p.startTime)
+		return p.position + time.Since(p.startTime)
 	}
 	return p.position
 }
@@ -0,0 +1,224 @@
+package decoder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"terminaltube/pkg/types"
+)
+
+// EXIF tags read by parseEXIF
+const (
+	exifTagMake             = 0x010F
+	exifTagModel            = 0x0110
+	exifTagOrientation      = 0x0112
+	exifTagDateTime         = 0x0132
+	exifTagExifIFD          = 0x8769
+	exifTagGPSIFD           = 0x8825
+	exifTagDateTimeOriginal = 0x9003
+	exifTagPixelXDimension  = 0xA002
+	exifTagPixelYDimension  = 0xA003
+)
+
+// EXIF field types
+const (
+	exifTypeASCII = 2
+	exifTypeShort = 3
+	exifTypeLong  = 4
+)
+
+// exifEntry is one tag of an image file directory
+type exifEntry struct {
+	tag, kind uint16
+	count     uint32
+	value     []byte // The 4-byte value field, holding the value or its offset
+}
+
+// exifReader reads values from the TIFF structure inside an EXIF segment
+type exifReader struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+// findEXIF returns the TIFF data of a JPEG's EXIF segment, or nil if it has none
+func findEXIF(data []byte) []byte {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil
+	}
+
+	for pos := 2; pos+4 <= len(data); {
+		if data[pos] != 0xFF {
+			return nil
+		}
+		marker := data[pos+1]
+		// Start of scan: the metadata segments are all behind us
+		if marker == 0xDA || marker == 0xD9 {
+			return nil
+		}
+
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return nil
+		}
+
+		segment := data[pos+4 : end]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return segment[6:]
+		}
+		pos = end
+	}
+	return nil
+}
+
+// parseEXIF extracts the metadata of a JPEG, or returns nil if it has no EXIF
+func parseEXIF(data []byte) (*types.ImageMetadata, error) {
+	tiff := findEXIF(data)
+	if tiff == nil {
+		return nil, nil
+	}
+	if len(tiff) < 8 {
+		return nil, fmt.Errorf("truncated EXIF header")
+	}
+
+	r := &exifReader{data: tiff}
+	switch string(tiff[:2]) {
+	case "II":
+		r.order = binary.LittleEndian
+	case "MM":
+		r.order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("invalid EXIF byte order")
+	}
+
+	ifd0, err := r.readIFD(r.order.Uint32(tiff[4:]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read EXIF: %w", err)
+	}
+
+	metadata := &types.ImageMetadata{Orientation: 1}
+	for _, entry := range ifd0 {
+		switch entry.tag {
+		case exifTagMake:
+			metadata.CameraMake = r.readString(entry)
+		case exifTagModel:
+			metadata.CameraModel = r.readString(entry)
+		case exifTagOrientation:
+			if orientation := r.readInt(entry); orientation >= 1 && orientation <= 8 {
+				metadata.Orientation = orientation
+			}
+		case exifTagDateTime:
+			if metadata.DateTaken == "" {
+				metadata.DateTaken = r.readString(entry)
+			}
+		case exifTagExifIFD:
+			// The photo's own tags; a broken sub-directory isn't fatal
+			if entries, err := r.readIFD(uint32(r.readInt(entry))); err == nil {
+				r.readExifIFD(entries, metadata)
+			}
+		case exifTagGPSIFD:
+			if entries, err := r.readIFD(uint32(r.readInt(entry))); err == nil {
+				metadata.HasGPS = len(entries) > 0
+			}
+		}
+	}
+
+	return metadata, nil
+}
+
+// readExifIFD fills in the tags of the EXIF sub-directory
+func (r *exifReader) readExifIFD(entries []exifEntry, metadata *types.ImageMetadata) {
+	for _, entry := range entries {
+		switch entry.tag {
+		case exifTagDateTimeOriginal:
+			// When the photo was taken beats when the file was last changed
+			if date := r.readString(entry); date != "" {
+				metadata.DateTaken = date
+			}
+		case exifTagPixelXDimension:
+			metadata.PixelWidth = r.readInt(entry)
+		case exifTagPixelYDimension:
+			metadata.PixelHeight = r.readInt(entry)
+		}
+	}
+}
+
+// readIFD reads the entries of the image file directory at offset
+func (r *exifReader) readIFD(offset uint32) ([]exifEntry, error) {
+	if int64(offset)+2 > int64(len(r.data)) {
+		return nil, fmt.Errorf("directory offset %d out of range", offset)
+	}
+
+	count := int(r.order.Uint16(r.data[offset:]))
+	start := int(offset) + 2
+	if start+count*12 > len(r.data) {
+		return nil, fmt.Errorf("directory at %d is truncated", offset)
+	}
+
+	entries := make([]exifEntry, count)
+	for i := range entries {
+		raw := r.data[start+i*12 : start+(i+1)*12]
+		entries[i] = exifEntry{
+			tag:   r.order.Uint16(raw[0:]),
+			kind:  r.order.Uint16(raw[2:]),
+			count: r.order.Uint32(raw[4:]),
+			value: raw[8:12],
+		}
+	}
+	return entries, nil
+}
+
+// readInt returns a SHORT or LONG value, or 0 for other types
+func (r *exifReader) readInt(entry exifEntry) int {
+	switch entry.kind {
+	case exifTypeShort:
+		return int(r.order.Uint16(entry.value))
+	case exifTypeLong:
+		return int(r.order.Uint32(entry.value))
+	default:
+		return 0
+	}
+}
+
+// readString returns an ASCII value without its terminator and padding
+func (r *exifReader) readString(entry exifEntry) string {
+	if entry.kind != exifTypeASCII {
+		return ""
+	}
+
+	// Up to 4 bytes are stored in place, longer values at an offset
+	value := entry.value[:min(entry.count, 4)]
+	if entry.count > 4 {
+		offset := r.order.Uint32(entry.value)
+		if int64(offset)+int64(entry.count) > int64(len(r.data)) {
+			return ""
+		}
+		value = r.data[offset : offset+entry.count]
+	}
+
+	return strings.TrimSpace(strings.TrimRight(string(value), "\x00"))
+}
+
+// orientationTransform returns the TransformImage arguments that display an
+// image with the given EXIF orientation upright
+func orientationTransform(orientation int) (int, bool) {
+	switch orientation {
+	case 2:
+		return 0, true // Mirrored
+	case 3:
+		return 2, false // Upside down
+	case 4:
+		return 2, true // Mirrored upside down
+	case 5:
+		return 1, true // Transposed
+	case 6:
+		return 1, false // Turned a quarter counter-clockwise
+	case 7:
+		return 3, true // Transversed
+	case 8:
+		return 3, false // Turned a quarter clockwise
+	default:
+		return 0, false
+	}
+}
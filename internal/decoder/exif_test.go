@@ -0,0 +1,131 @@
+package decoder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"terminaltube/pkg/types"
+	"testing"
+)
+
+// Offsets in the TIFF data built by buildTIFF
+const (
+	testIFD0Offset = 8
+	testExifOffset = 62  // IFD0: 2 + 4 entries * 12 + 4
+	testGPSOffset  = 92  // EXIF IFD: 2 + 2 entries * 12 + 4
+	testDataOffset = 110 // GPS IFD: 2 + 1 entry * 12 + 4
+)
+
+// buildTIFF returns EXIF TIFF data in the given byte order with a camera make,
+// orientation 6, an EXIF sub-directory (date taken, pixel width) and a GPS
+// sub-directory
+func buildTIFF(order binary.ByteOrder) []byte {
+	var b bytes.Buffer
+	put16 := func(v uint16) { binary.Write(&b, order, v) }
+	put32 := func(v uint32) { binary.Write(&b, order, v) }
+	entry := func(tag, kind uint16, count, value uint32) {
+		put16(tag)
+		put16(kind)
+		put32(count)
+		if kind == exifTypeShort {
+			// SHORT values sit in the first two bytes of the field
+			put16(uint16(value))
+			put16(0)
+		} else {
+			put32(value)
+		}
+	}
+
+	if order == binary.LittleEndian {
+		b.WriteString("II")
+	} else {
+		b.WriteString("MM")
+	}
+	put16(42)
+	put32(testIFD0Offset)
+
+	put16(4)
+	entry(exifTagMake, exifTypeASCII, 6, testDataOffset)
+	entry(exifTagOrientation, exifTypeShort, 1, 6)
+	entry(exifTagExifIFD, exifTypeLong, 1, testExifOffset)
+	entry(exifTagGPSIFD, exifTypeLong, 1, testGPSOffset)
+	put32(0)
+
+	put16(2)
+	entry(exifTagDateTimeOriginal, exifTypeASCII, 20, testDataOffset+6)
+	entry(exifTagPixelXDimension, exifTypeLong, 1, 4000)
+	put32(0)
+
+	put16(1)
+	entry(0, 1, 4, 0x02020000) // GPSVersionID
+	put32(0)
+
+	b.WriteString("Canon\x00")
+	b.WriteString("2024:01:02 03:04:05\x00")
+	return b.Bytes()
+}
+
+// buildJPEG wraps TIFF data in a JPEG APP1 segment
+func buildJPEG(tiff []byte) []byte {
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	jpeg := []byte{0xFF, 0xD8, 0xFF, 0xE1}
+	jpeg = binary.BigEndian.AppendUint16(jpeg, uint16(len(segment)+2))
+	jpeg = append(jpeg, segment...)
+	return append(jpeg, 0xFF, 0xDA, 0x00, 0x02)
+}
+
+func TestParseEXIF(t *testing.T) {
+	full := types.ImageMetadata{
+		CameraMake:  "Canon",
+		Orientation: 6,
+		DateTaken:   "2024:01:02 03:04:05",
+		PixelWidth:  4000,
+		HasGPS:      true,
+	}
+
+	badOffset := buildTIFF(binary.BigEndian)
+	binary.BigEndian.PutUint32(badOffset[4:], 0xFFFFFFF0)
+
+	badString := buildTIFF(binary.LittleEndian)
+	binary.LittleEndian.PutUint32(badString[testIFD0Offset+2+8:], 0xFFFFFFFF)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    *types.ImageMetadata
+		wantErr bool
+	}{
+		{"little endian", buildJPEG(buildTIFF(binary.LittleEndian)), &full, false},
+		{"big endian", buildJPEG(buildTIFF(binary.BigEndian)), &full, false},
+		{"not a JPEG", []byte("GIF89a"), nil, false},
+		{"no EXIF segment", []byte{0xFF, 0xD8, 0xFF, 0xDA, 0x00, 0x02}, nil, false},
+		{"segment longer than file", buildJPEG(buildTIFF(binary.LittleEndian))[:40], nil, false},
+		{"truncated header", buildJPEG([]byte("II*\x00")), nil, true},
+		{"invalid byte order", buildJPEG(append([]byte("XX"), buildTIFF(binary.LittleEndian)[2:]...)), nil, true},
+		{"directory offset out of range", buildJPEG(badOffset), nil, true},
+		{
+			// IFD0 is intact; the sub-directories and string data are cut off
+			"truncated sub-directories",
+			buildJPEG(buildTIFF(binary.LittleEndian)[:testExifOffset+10]),
+			&types.ImageMetadata{Orientation: 6},
+			false,
+		},
+		{
+			"string offset out of range",
+			buildJPEG(badString),
+			&types.ImageMetadata{Orientation: 6, DateTaken: full.DateTaken, PixelWidth: 4000, HasGPS: true},
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseEXIF(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseEXIF() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("parseEXIF() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,8 +2,11 @@ package decoder
 
 import (
 	"fmt"
+	"image"
+	"image/draw"
 	"image/gif"
 	"os"
+	"sync"
 	"terminaltube/pkg/types"
 	"time"
 )
@@ -11,12 +14,22 @@ import (
 // GIFDecoder handles animated GIF decoding
 type GIFDecoder struct {
 	currentGIF *gif.GIF
+	frames     []*image.RGBA // Fully composited frames, transparent where nothing was drawn
 	filename   string
+	stopChan   chan struct{}
+
+	// mu guards the playback state shared with the frame goroutine
+	mu     sync.Mutex
+	paused bool
+	step   int           // Frames to move by, requested by StepFrames
+	wake   chan struct{} // Wakes the frame goroutine for a resume or step
 }
 
 // NewGIFDecoder creates a new GIF decoder
 func NewGIFDecoder() *GIFDecoder {
-	return &GIFDecoder{}
+	return &GIFDecoder{
+		wake: make(chan struct{}, 1),
+	}
 }
 
 // IsSupported checks if the file is a GIF
@@ -47,6 +60,7 @@ func (d *GIFDecoder) LoadGIF(filename string) (*types.MediaInfo, error) {
 	}
 
 	d.currentGIF = gifData
+	d.frames = composeFrames(gifData)
 	d.filename = filename
 
 	// Calculate total duration
@@ -56,10 +70,10 @@ func (d *GIFDecoder) LoadGIF(filename string) (*types.MediaInfo, error) {
 		totalDuration += time.Duration(delay) * time.Millisecond * 10
 	}
 
-	// Get dimensions from first frame
+	// Get dimensions from the composited canvas
 	var width, height int
-	if len(gifData.Image) > 0 {
-		bounds := gifData.Image[0].Bounds()
+	if len(d.frames) > 0 {
+		bounds := d.frames[0].Bounds()
 		width = bounds.Dx()
 		height = bounds.Dy()
 	}
@@ -86,6 +100,51 @@ func (d *GIFDecoder) LoadGIF(filename string) (*types.MediaInfo, error) {
 	return mediaInfo, nil
 }
 
+// composeFrames draws each GIF frame over the canvas left by the previous ones,
+// honouring the disposal methods, so every frame is a complete picture
+// Areas no frame has drawn stay transparent, for the renderer to composite.
+func composeFrames(g *gif.GIF) []*image.RGBA {
+	if len(g.Image) == 0 {
+		return nil
+	}
+
+	width, height := g.Config.Width, g.Config.Height
+	if width == 0 || height == 0 {
+		bounds := g.Image[0].Bounds()
+		width, height = bounds.Max.X, bounds.Max.Y
+	}
+
+	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+	frames := make([]*image.RGBA, len(g.Image))
+
+	for i, frame := range g.Image {
+		disposal := byte(gif.DisposalNone)
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+
+		var previous *image.RGBA
+		if disposal == gif.DisposalPrevious {
+			previous = image.NewRGBA(canvas.Bounds())
+			copy(previous.Pix, canvas.Pix)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+
+		frames[i] = image.NewRGBA(canvas.Bounds())
+		copy(frames[i].Pix, canvas.Pix)
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+
+	return frames
+}
+
 // GetFrameCount returns the total number of frames
 func (d *GIFDecoder) GetFrameCount() int {
 	if d.currentGIF == nil {
@@ -105,7 +164,7 @@ func (d *GIFDecoder) GetFrame(frameIndex int) (*types.Frame, error) {
 	}
 
 	// Get the frame image
-	frameImage := d.currentGIF.Image[frameIndex]
+	frameImage := d.frames[frameIndex]
 
 	// Calculate frame duration (GIF delays are in centiseconds)
 	var frameDuration float64
@@ -130,42 +189,140 @@ func (d *GIFDecoder) GetFrame(frameIndex int) (*types.Frame, error) {
 	return frame, nil
 }
 
-// GetFrameChannel returns a channel that yields frames with proper timing
+// GetFrameChannel returns a channel that yields frames with proper timing,
+// looping until the decoder is closed
 func (d *GIFDecoder) GetFrameChannel() (<-chan *types.Frame, error) {
 	if d.currentGIF == nil {
 		return nil, fmt.Errorf("no GIF loaded")
 	}
+	if len(d.frames) == 0 {
+		return nil, fmt.Errorf("GIF has no frames")
+	}
+
+	// Prepare every frame up front so the goroutine doesn't share the GIF
+	frames := make([]*types.Frame, len(d.frames))
+	for i := range frames {
+		frame, err := d.GetFrame(i)
+		if err != nil {
+			return nil, err
+		}
+		frames[i] = frame
+	}
 
 	frameChan := make(chan *types.Frame, 1)
+	d.stopChan = make(chan struct{})
+	stop := d.stopChan
 
 	go func() {
 		defer close(frameChan)
 
+		i := 0
 		for {
-			for i := 0; i < len(d.currentGIF.Image); i++ {
-				frame, err := d.GetFrame(i)
-				if err != nil {
-					return
-				}
-
-				frameChan <- frame
-
-				// Wait for the frame duration
-				if frame.Duration > 0 {
-					time.Sleep(time.Duration(frame.Duration * float64(time.Second)))
-				} else {
-					// Default delay if no delay specified
-					time.Sleep(100 * time.Millisecond)
-				}
+			select {
+			case frameChan <- frames[i]:
+			case <-stop:
+				return
+			}
+
+			// Wait for the frame duration
+			delay := time.Duration(frames[i].Duration * float64(time.Second))
+			if delay <= 0 {
+				// Default delay if no delay specified
+				delay = 100 * time.Millisecond
 			}
+			step, ok := d.waitFrame(delay, stop)
+			if !ok {
+				return
+			}
+			i = ((i+step)%len(frames) + len(frames)) % len(frames)
 		}
 	}()
 
 	return frameChan, nil
 }
 
+// waitFrame waits out a frame's delay and returns how many frames to move
+// on by: one once the delay has passed while playing, or as many as
+// StepFrames asked for; it reports false if the decoder was closed
+func (d *GIFDecoder) waitFrame(delay time.Duration, stop <-chan struct{}) (int, bool) {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	due := false
+	for {
+		select {
+		case <-stop:
+			return 0, false
+		case <-timer.C:
+			due = true
+		case <-d.wake:
+		}
+
+		d.mu.Lock()
+		step, paused := d.step, d.paused
+		d.step = 0
+		d.mu.Unlock()
+
+		if step != 0 {
+			return step, true
+		}
+		if due && !paused {
+			return 1, true
+		}
+	}
+}
+
+// signalWake wakes the frame goroutine if it is waiting
+func (d *GIFDecoder) signalWake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Pause holds the current frame until Resume
+func (d *GIFDecoder) Pause() {
+	d.mu.Lock()
+	d.paused = true
+	d.mu.Unlock()
+}
+
+// Resume continues the animation after Pause
+func (d *GIFDecoder) Resume() {
+	d.mu.Lock()
+	d.paused = false
+	d.mu.Unlock()
+	d.signalWake()
+}
+
+// IsPaused returns true if the animation is paused
+func (d *GIFDecoder) IsPaused() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.paused
+}
+
+// StepFrames moves the animation n frames forward, or back if n is negative,
+// wrapping around at either end; it works while paused too
+func (d *GIFDecoder) StepFrames(n int) {
+	d.mu.Lock()
+	d.step += n
+	d.mu.Unlock()
+	d.signalWake()
+}
+
 // Close cleans up the decoder
 func (d *GIFDecoder) Close() {
+	if d.stopChan != nil {
+		select {
+		case <-d.stopChan:
+			// Already closed
+		default:
+			close(d.stopChan)
+		}
+	}
+
 	d.currentGIF = nil
+	d.frames = nil
 	d.filename = ""
 }
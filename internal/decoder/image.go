@@ -1,6 +1,7 @@
 package decoder
 
 import (
+	"bytes"
 	"fmt"
 	"image"
 	_ "image/gif"
@@ -37,18 +38,27 @@ func (d *ImageDecoder) IsSupported(filename string) bool {
 
 // DecodeImage decodes a static image file
 func (d *ImageDecoder) DecodeImage(filename string) (image.Image, *types.MediaInfo, error) {
-	file, err := os.Open(filename)
+	data, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to open image file: %w", err)
 	}
-	defer file.Close()
 
 	// Decode the image
-	img, format, err := image.Decode(file)
+	img, format, err := image.Decode(bytes.NewReader(data))
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to decode image: %w", err)
 	}
 
+	// Turn photos upright; broken EXIF only costs the metadata
+	var metadata *types.ImageMetadata
+	if format == "jpeg" {
+		metadata, _ = parseEXIF(data)
+		if metadata != nil {
+			quarterTurns, mirror := orientationTransform(metadata.Orientation)
+			img = TransformImage(img, quarterTurns, mirror)
+		}
+	}
+
 	// Get image dimensions
 	bounds := img.Bounds()
 
@@ -63,6 +73,7 @@ func (d *ImageDecoder) DecodeImage(filename string) (image.Image, *types.MediaIn
 		AudioCodec: "",
 		VideoCodec: format,
 		FrameCount: 1,
+		Metadata:   metadata,
 	}
 
 	return img, mediaInfo, nil
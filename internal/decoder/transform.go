@@ -0,0 +1,55 @@
+package decoder
+
+import (
+	"image"
+	"image/draw"
+)
+
+// TransformImage rotates an image clockwise by the given number of quarter
+// turns, then mirrors it left to right when mirror is set
+// The image is returned untouched when there is nothing to do.
+func TransformImage(img image.Image, quarterTurns int, mirror bool) image.Image {
+	quarterTurns = (quarterTurns%4 + 4) % 4
+	if quarterTurns == 0 && !mirror {
+		return img
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	src, ok := img.(*image.RGBA)
+	if !ok || bounds.Min != (image.Point{}) {
+		src = image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+	}
+
+	dstWidth, dstHeight := width, height
+	if quarterTurns%2 == 1 {
+		dstWidth, dstHeight = height, width
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dx, dy int
+			switch quarterTurns {
+			case 0:
+				dx, dy = x, y
+			case 1:
+				dx, dy = height-1-y, x
+			case 2:
+				dx, dy = width-1-x, height-1-y
+			case 3:
+				dx, dy = y, width-1-x
+			}
+			if mirror {
+				dx = dstWidth - 1 - dx
+			}
+
+			i := y*src.Stride + x*4
+			j := dy*dst.Stride + dx*4
+			copy(dst.Pix[j:j+4], src.Pix[i:i+4])
+		}
+	}
+
+	return dst
+}
@@ -10,6 +10,7 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
+	"sync"
 	"terminaltube/pkg/types"
 	"time"
 )
@@ -31,6 +32,13 @@ type VideoDecoder struct {
 	ffmpegCmd    *exec.Cmd
 	frameReader  io.ReadCloser
 	stopChan     chan struct{}
+
+	// mu guards the streaming state shared with the frame goroutine
+	mu          sync.Mutex
+	pendingSize *image.Point // Scale requested by Rescale, applied before the next frame
+	pendingSeek *float64     // Position requested by Seek, applied before the next frame
+	paused      bool
+	wake        chan struct{} // Wakes the paused frame goroutine for a seek or resume
 }
 
 // FFProbeOutput represents the JSON output from ffprobe
@@ -61,6 +69,7 @@ type FFProbeFormat struct {
 func NewVideoDecoder() *VideoDecoder {
 	return &VideoDecoder{
 		stopChan: make(chan struct{}),
+		wake:     make(chan struct{}, 1),
 	}
 }
 
@@ -318,34 +327,15 @@ func (d *VideoDecoder) GetFrameChannel() (<-chan *types.Frame, error) {
 		outHeight = 90
 	}
 
-	// Start ffmpeg process to stream scaled frames
-	// Using -an to ignore audio, scale filter with lanczos for high quality
-	cmd := exec.Command("ffmpeg",
-		"-i", d.filename,
-		"-an", // No audio
-		"-vf", fmt.Sprintf("scale=%d:%d:flags=lanczos", outWidth, outHeight),
-		"-f", "rawvideo",
-		"-pix_fmt", "rgb24",
-		"-v", "quiet",
-		"pipe:1",
-	)
-
-	stdout, err := cmd.StdoutPipe()
+	d.stopChan = make(chan struct{})
+	stdout, err := d.startStream(0, outWidth, outHeight)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
-	}
-
-	if err := cmd.Start(); err != nil {
-		return nil, fmt.Errorf("failed to start ffmpeg: %w", err)
+		return nil, err
 	}
 
-	d.ffmpegCmd = cmd
-	d.frameReader = stdout
-	d.stopChan = make(chan struct{})
-
 	go func() {
 		defer close(frameChan)
-		defer cmd.Wait()
+		defer d.stopStream()
 
 		frameSize := outWidth * outHeight * 3
 		frameBuffer := make([]byte, frameSize)
@@ -362,6 +352,34 @@ func (d *VideoDecoder) GetFrameChannel() (<-chan *types.Frame, error) {
 			default:
 			}
 
+			// Hold frames back while paused, and shift the schedule by the
+			// time spent paused so playback carries on without skipping
+			pausedFor, stopped := d.waitWhilePaused()
+			if stopped {
+				return
+			}
+			startTime = startTime.Add(pausedFor)
+
+			// Restart the stream for a seek, or to switch to a new scale
+			// where the old stream left off
+			if position, size, seek, restart := d.takeRestart(frameIndex, image.Point{X: outWidth, Y: outHeight}); restart {
+				d.stopStream()
+				stdout, err := d.startStream(position, size.X, size.Y)
+				if err != nil {
+					return
+				}
+				outWidth, outHeight = size.X, size.Y
+				frameSize = outWidth * outHeight * 3
+				frameBuffer = make([]byte, frameSize)
+				reader = bufio.NewReaderSize(stdout, frameSize*4)
+
+				if seek {
+					// The frame at the new position is due right away
+					frameIndex = int(position * d.fps)
+					startTime = time.Now().Add(-time.Duration(frameIndex) * frameDuration)
+				}
+			}
+
 			// Read one frame of raw RGB data
 			n, err := io.ReadFull(reader, frameBuffer)
 			if err != nil {
@@ -407,26 +425,211 @@ func (d *VideoDecoder) GetFrameChannel() (<-chan *types.Frame, error) {
 				// Channel full, skip frame and move on
 				frameIndex++
 			}
+			d.setCurrentFrame(frameIndex)
 		}
 	}()
 
 	return frameChan, nil
 }
 
+// Rescale changes the size streamed frames are scaled to
+// ffmpeg is restarted at the current position with the new scale filter, so
+// playback continues where it was; frames already decoded keep the old size.
+func (d *VideoDecoder) Rescale(width, height int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.renderWidth, d.renderHeight = width, height
+	d.pendingSize = &image.Point{X: width, Y: height}
+}
+
+// takeRestart returns the position and size to restart the stream at before
+// frame frameIndex, streamed at size current, and whether that is for a seek
+// It reports false when neither a seek nor a new size has been requested.
+// A rescale on its own restarts at frameIndex, so playback carries on.
+func (d *VideoDecoder) takeRestart(frameIndex int, current image.Point) (float64, image.Point, bool, bool) {
+	size, rescale := d.takeRescale()
+	rescale = rescale && size != current
+	position, seek := d.takeSeek()
+	if !rescale && !seek {
+		return 0, current, false, false
+	}
+
+	if !rescale {
+		size = current
+	}
+	if !seek {
+		position = float64(frameIndex) / d.fps
+	}
+	return position, size, seek, true
+}
+
+// takeRescale returns the size requested by Rescale since the last call
+func (d *VideoDecoder) takeRescale() (image.Point, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.pendingSize == nil {
+		return image.Point{}, false
+	}
+	size := *d.pendingSize
+	d.pendingSize = nil
+	return size, true
+}
+
+// takeSeek returns the position requested by Seek since the last call
+func (d *VideoDecoder) takeSeek() (float64, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.pendingSeek == nil {
+		return 0, false
+	}
+	position := *d.pendingSeek
+	d.pendingSeek = nil
+	return position, true
+}
+
+// waitWhilePaused blocks the frame goroutine while playback is paused,
+// returning how long it waited and whether the decoder was closed meanwhile
+// A seek wakes it for a single frame, so the new position is shown even
+// while paused.
+func (d *VideoDecoder) waitWhilePaused() (time.Duration, bool) {
+	start := time.Now()
+	for {
+		d.mu.Lock()
+		waiting := d.paused && d.pendingSeek == nil
+		d.mu.Unlock()
+		if !waiting {
+			return time.Since(start), false
+		}
+
+		select {
+		case <-d.stopChan:
+			return 0, true
+		case <-d.wake:
+		}
+	}
+}
+
+// signalWake wakes the frame goroutine if it is waiting while paused
+func (d *VideoDecoder) signalWake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Pause stops streamed frames until Resume
+func (d *VideoDecoder) Pause() {
+	d.mu.Lock()
+	d.paused = true
+	d.mu.Unlock()
+}
+
+// Resume continues streaming frames after Pause
+func (d *VideoDecoder) Resume() {
+	d.mu.Lock()
+	d.paused = false
+	d.mu.Unlock()
+	d.signalWake()
+}
+
+// IsPaused returns true if streaming is paused
+func (d *VideoDecoder) IsPaused() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.paused
+}
+
+// startStream starts ffmpeg streaming raw frames from position (in seconds)
+// at the given scale
+func (d *VideoDecoder) startStream(position float64, width, height int) (io.Reader, error) {
+	cmd := exec.Command("ffmpeg", d.streamArgs(position, width, height)...)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start ffmpeg: %w", err)
+	}
+
+	d.mu.Lock()
+	d.ffmpegCmd = cmd
+	d.frameReader = stdout
+	d.mu.Unlock()
+
+	return stdout, nil
+}
+
+// streamArgs returns the ffmpeg arguments streaming raw RGB frames from
+// position (in seconds) at the given scale
+func (d *VideoDecoder) streamArgs(position float64, width, height int) []string {
+	var args []string
+	if position > 0 {
+		// Seeking before the input is fast and frame-accurate
+		args = append(args, "-ss", fmt.Sprintf("%.3f", position))
+	}
+
+	// Using -an to ignore audio, scale filter with lanczos for high quality
+	args = append(args,
+		"-i", d.filename,
+		"-an", // No audio
+		"-vf", fmt.Sprintf("scale=%d:%d:flags=lanczos", width, height),
+		"-f", "rawvideo",
+		"-pix_fmt", "rgb24",
+		"-v", "quiet",
+		"pipe:1",
+	)
+	return args
+}
+
+// stopStream stops the running ffmpeg process, if any
+func (d *VideoDecoder) stopStream() {
+	d.mu.Lock()
+	cmd, reader := d.ffmpegCmd, d.frameReader
+	d.ffmpegCmd, d.frameReader = nil, nil
+	d.mu.Unlock()
+
+	if cmd != nil && cmd.Process != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}
+	if reader != nil {
+		reader.Close()
+	}
+}
+
+// setCurrentFrame records the streaming position
+func (d *VideoDecoder) setCurrentFrame(frameIndex int) {
+	d.mu.Lock()
+	d.currentFrame = frameIndex
+	d.mu.Unlock()
+}
+
 // Seek moves to a specific time position in the video
+// While frames are streaming, ffmpeg is restarted at the new position and the
+// next frame comes from there.
 func (d *VideoDecoder) Seek(timestamp float64) error {
 	if timestamp < 0 || timestamp > d.duration {
 		return fmt.Errorf("timestamp out of range: %f", timestamp)
 	}
 
-	frameIndex := int(timestamp * d.fps)
-	d.currentFrame = frameIndex
+	d.mu.Lock()
+	d.currentFrame = int(timestamp * d.fps)
+	d.pendingSeek = &timestamp
+	d.mu.Unlock()
+	d.signalWake()
 
 	return nil
 }
 
 // GetCurrentPosition returns the current playback position in seconds
 func (d *VideoDecoder) GetCurrentPosition() float64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
 	return float64(d.currentFrame) / d.fps
 }
 
@@ -443,18 +646,10 @@ func (d *VideoDecoder) Close() error {
 	}
 
 	// Kill ffmpeg process if running
-	if d.ffmpegCmd != nil && d.ffmpegCmd.Process != nil {
-		d.ffmpegCmd.Process.Kill()
-		d.ffmpegCmd.Wait()
-	}
-
-	// Close frame reader
-	if d.frameReader != nil {
-		d.frameReader.Close()
-	}
+	d.stopStream()
 
 	d.filename = ""
-	d.currentFrame = 0
+	d.setCurrentFrame(0)
 	return nil
 }
 
@@ -470,7 +665,9 @@ func (d *VideoDecoder) GetVideoInfo() map[string]interface{} {
 	info["has_audio"] = d.hasAudio
 	info["audio_codec"] = d.audioCodec
 	info["video_codec"] = d.videoCodec
+	d.mu.Lock()
 	info["current_frame"] = d.currentFrame
+	d.mu.Unlock()
 
 	return info
 }
@@ -0,0 +1,90 @@
+package decoder
+
+import (
+	"image"
+	"reflect"
+	"testing"
+)
+
+func TestTakeRestart(t *testing.T) {
+	current := image.Point{X: 160, Y: 90}
+
+	tests := []struct {
+		name         string
+		request      func(d *VideoDecoder)
+		frameIndex   int
+		wantRestart  bool
+		wantPosition float64
+		wantSize     image.Point
+		wantSeek     bool
+	}{
+		{"nothing requested", func(d *VideoDecoder) {}, 125, false, 0, current, false},
+		{
+			"rescale keeps the playback position",
+			func(d *VideoDecoder) { d.Rescale(320, 180) },
+			125, true, 5, image.Point{X: 320, Y: 180}, false,
+		},
+		{"rescale to the current size", func(d *VideoDecoder) { d.Rescale(160, 90) }, 125, false, 0, current, false},
+		{"seek", func(d *VideoDecoder) { d.Seek(30) }, 125, true, 30, current, true},
+		{
+			"seek and rescale",
+			func(d *VideoDecoder) {
+				d.Rescale(320, 180)
+				d.Seek(30)
+			},
+			125, true, 30, image.Point{X: 320, Y: 180}, true,
+		},
+		{
+			"latest rescale wins",
+			func(d *VideoDecoder) {
+				d.Rescale(320, 180)
+				d.Rescale(200, 100)
+			},
+			50, true, 2, image.Point{X: 200, Y: 100}, false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &VideoDecoder{fps: 25, duration: 60}
+			tt.request(d)
+
+			position, size, seek, restart := d.takeRestart(tt.frameIndex, current)
+			if restart != tt.wantRestart || position != tt.wantPosition || size != tt.wantSize || seek != tt.wantSeek {
+				t.Errorf("takeRestart() = %v, %v, seek %v, restart %v; want %v, %v, seek %v, restart %v",
+					position, size, seek, restart, tt.wantPosition, tt.wantSize, tt.wantSeek, tt.wantRestart)
+			}
+
+			// Requests are taken once
+			if _, _, _, again := d.takeRestart(tt.frameIndex, size); again {
+				t.Errorf("takeRestart() restarted twice for one request")
+			}
+		})
+	}
+}
+
+func TestStreamArgs(t *testing.T) {
+	d := &VideoDecoder{filename: "clip.mp4"}
+	tail := []string{
+		"-i", "clip.mp4", "-an", "-vf", "scale=320:180:flags=lanczos",
+		"-f", "rawvideo", "-pix_fmt", "rgb24", "-v", "quiet", "pipe:1",
+	}
+
+	tests := []struct {
+		name     string
+		position float64
+		want     []string
+	}{
+		{"from the start", 0, tail},
+		{"from the restart position", 5, append([]string{"-ss", "5.000"}, tail...)},
+		{"fractional position", 12.3456, append([]string{"-ss", "12.346"}, tail...)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.streamArgs(tt.position, 320, 180); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("streamArgs(%v) = %q, want %q", tt.position, got, tt.want)
+			}
+		})
+	}
+}
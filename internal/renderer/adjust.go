@@ -0,0 +1,155 @@
+package renderer
+
+import (
+	"image"
+	"image/draw"
+	"math"
+	"terminaltube/pkg/types"
+)
+
+// adjustImage applies the color and detail adjustments to an image
+// Every renderer calls it after scaling, so the work is done at output size.
+// The image is returned untouched when the adjustments are neutral.
+func adjustImage(img image.Image, adj types.Adjustments) image.Image {
+	if adj.IsNeutral() {
+		return img
+	}
+
+	bounds := img.Bounds()
+	src, ok := img.(*image.RGBA)
+	if !ok {
+		src = image.NewRGBA(bounds)
+		draw.Draw(src, bounds, img, bounds.Min, draw.Src)
+	}
+
+	dst := image.NewRGBA(bounds)
+	tone := toneCurve(adj)
+	matrix, colorize := colorMatrix(adj)
+
+	for y := 0; y < bounds.Dy(); y++ {
+		srcRow := src.Pix[y*src.Stride : y*src.Stride+bounds.Dx()*4]
+		dstRow := dst.Pix[y*dst.Stride : y*dst.Stride+bounds.Dx()*4]
+		for i := 0; i < len(srcRow); i += 4 {
+			a := srcRow[i+3]
+			if a == 0 {
+				continue
+			}
+
+			// Adjust the straight color of translucent pixels, not the premultiplied one
+			r, g, b := srcRow[i], srcRow[i+1], srcRow[i+2]
+			if a < 255 {
+				r, g, b = unpremultiply(r, a), unpremultiply(g, a), unpremultiply(b, a)
+			}
+
+			r, g, b = tone[r], tone[g], tone[b]
+			if colorize {
+				fr, fg, fb := float64(r), float64(g), float64(b)
+				r = clampChannel(matrix[0]*fr + matrix[1]*fg + matrix[2]*fb)
+				g = clampChannel(matrix[3]*fr + matrix[4]*fg + matrix[5]*fb)
+				b = clampChannel(matrix[6]*fr + matrix[7]*fg + matrix[8]*fb)
+			}
+			if adj.Invert {
+				r, g, b = 255-r, 255-g, 255-b
+			}
+			if a < 255 {
+				r, g, b = premultiply(r, a), premultiply(g, a), premultiply(b, a)
+			}
+			dstRow[i], dstRow[i+1], dstRow[i+2], dstRow[i+3] = r, g, b, a
+		}
+	}
+
+	if adj.Sharpen > 0 {
+		dst = sharpenImage(dst, adj.Sharpen)
+	}
+
+	return dst
+}
+
+// unpremultiply returns the straight value of a premultiplied channel
+func unpremultiply(v, a uint8) uint8 {
+	return uint8(min(255, (uint32(v)*255+uint32(a)/2)/uint32(a)))
+}
+
+// premultiply scales a straight channel value by alpha
+func premultiply(v, a uint8) uint8 {
+	return uint8((uint32(v)*uint32(a) + 127) / 255)
+}
+
+// toneCurve builds the per-channel lookup table for brightness, contrast and gamma
+func toneCurve(adj types.Adjustments) [256]uint8 {
+	var curve [256]uint8
+	gamma := adj.Gamma
+	if gamma <= 0 {
+		gamma = 1
+	}
+
+	for i := range curve {
+		// Brightness is additive, contrast multiplies around the midpoint
+		v := float64(i)/255 + adj.Brightness
+		v = (v-0.5)*adj.Contrast + 0.5
+		v = math.Max(0, math.Min(1, v))
+		v = math.Pow(v, 1/gamma)
+		curve[i] = clampChannel(v * 255)
+	}
+	return curve
+}
+
+// colorMatrix returns the RGB matrix combining saturation and hue rotation,
+// and whether it differs from the identity
+// Both follow the luminance-preserving matrices of the CSS filter effects spec.
+func colorMatrix(adj types.Adjustments) ([9]float64, bool) {
+	identity := [9]float64{1, 0, 0, 0, 1, 0, 0, 0, 1}
+	if adj.Saturation == 1 && math.Mod(adj.HueShift, 360) == 0 {
+		return identity, false
+	}
+
+	s := adj.Saturation
+	saturate := [9]float64{
+		0.213 + 0.787*s, 0.715 - 0.715*s, 0.072 - 0.072*s,
+		0.213 - 0.213*s, 0.715 + 0.285*s, 0.072 - 0.072*s,
+		0.213 - 0.213*s, 0.715 - 0.715*s, 0.072 + 0.928*s,
+	}
+
+	angle := adj.HueShift * math.Pi / 180
+	c, n := math.Cos(angle), math.Sin(angle)
+	hue := [9]float64{
+		0.213 + c*0.787 - n*0.213, 0.715 - c*0.715 - n*0.715, 0.072 - c*0.072 + n*0.928,
+		0.213 - c*0.213 + n*0.143, 0.715 + c*0.285 + n*0.140, 0.072 - c*0.072 - n*0.283,
+		0.213 - c*0.213 - n*0.787, 0.715 - c*0.715 + n*0.715, 0.072 + c*0.928 + n*0.072,
+	}
+
+	var combined [9]float64
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			for k := 0; k < 3; k++ {
+				combined[row*3+col] += hue[row*3+k] * saturate[k*3+col]
+			}
+		}
+	}
+	return combined, true
+}
+
+// sharpenImage applies a 3x3 unsharp mask of the given strength
+func sharpenImage(img *image.RGBA, amount float64) *image.RGBA {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(bounds)
+	copy(dst.Pix, img.Pix)
+
+	for y := 1; y < height-1; y++ {
+		for x := 1; x < width-1; x++ {
+			i := y*img.Stride + x*4
+			for ch := 0; ch < 3; ch++ {
+				var blur int
+				for dy := -1; dy <= 1; dy++ {
+					row := i + dy*img.Stride
+					blur += int(img.Pix[row-4+ch]) + int(img.Pix[row+ch]) + int(img.Pix[row+4+ch])
+				}
+				v := float64(img.Pix[i+ch])
+				dst.Pix[i+ch] = clampChannel(v + amount*(v-float64(blur)/9))
+			}
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,106 @@
+package renderer
+
+import (
+	"image"
+	"image/color"
+	"terminaltube/pkg/types"
+	"testing"
+)
+
+func TestAdjustImage(t *testing.T) {
+	// Grays, one color, a half-transparent gray (premultiplied 128 straight)
+	// and a fully transparent pixel
+	input := []color.RGBA{
+		{0, 0, 0, 255},
+		{64, 64, 64, 255},
+		{100, 100, 100, 255},
+		{200, 100, 50, 255},
+		{255, 255, 255, 255},
+		{64, 64, 64, 128},
+		{0, 0, 0, 0},
+	}
+
+	adjust := func(change func(*types.Adjustments)) types.Adjustments {
+		adj := types.DefaultAdjustments()
+		change(&adj)
+		return adj
+	}
+
+	tests := []struct {
+		name string
+		adj  types.Adjustments
+		want []color.RGBA
+	}{
+		{
+			"brightness +0.2",
+			adjust(func(a *types.Adjustments) { a.Brightness = 0.2 }),
+			[]color.RGBA{
+				{51, 51, 51, 255}, {115, 115, 115, 255}, {151, 151, 151, 255},
+				{251, 151, 101, 255}, {255, 255, 255, 255}, {90, 90, 90, 128}, {},
+			},
+		},
+		{
+			"brightness -0.2",
+			adjust(func(a *types.Adjustments) { a.Brightness = -0.2 }),
+			[]color.RGBA{
+				{0, 0, 0, 255}, {13, 13, 13, 255}, {49, 49, 49, 255},
+				{149, 49, 0, 255}, {204, 204, 204, 255}, {39, 39, 39, 128}, {},
+			},
+		},
+		{
+			"contrast 1.5",
+			adjust(func(a *types.Adjustments) { a.Contrast = 1.5 }),
+			[]color.RGBA{
+				{0, 0, 0, 255}, {32, 32, 32, 255}, {86, 86, 86, 255},
+				{236, 86, 11, 255}, {255, 255, 255, 255}, {64, 64, 64, 128}, {},
+			},
+		},
+		{
+			"gamma 2",
+			adjust(func(a *types.Adjustments) { a.Gamma = 2 }),
+			[]color.RGBA{
+				{0, 0, 0, 255}, {128, 128, 128, 255}, {160, 160, 160, 255},
+				{226, 160, 113, 255}, {255, 255, 255, 255}, {91, 91, 91, 128}, {},
+			},
+		},
+		{
+			"invert",
+			adjust(func(a *types.Adjustments) { a.Invert = true }),
+			[]color.RGBA{
+				{255, 255, 255, 255}, {191, 191, 191, 255}, {155, 155, 155, 255},
+				{55, 155, 205, 255}, {0, 0, 0, 255}, {64, 64, 64, 128}, {},
+			},
+		},
+		{
+			"saturation 0",
+			adjust(func(a *types.Adjustments) { a.Saturation = 0 }),
+			[]color.RGBA{
+				{0, 0, 0, 255}, {64, 64, 64, 255}, {100, 100, 100, 255},
+				{118, 118, 118, 255}, {255, 255, 255, 255}, {64, 64, 64, 128}, {},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := image.NewRGBA(image.Rect(0, 0, len(input), 1))
+			for x, c := range input {
+				img.SetRGBA(x, 0, c)
+			}
+
+			adjusted := adjustImage(img, tt.adj)
+			for x, want := range tt.want {
+				if got := color.RGBAModel.Convert(adjusted.At(x, 0)).(color.RGBA); got != want {
+					t.Errorf("pixel %v became %v, want %v", input[x], got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAdjustImageNeutral(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	if adjustImage(img, types.DefaultAdjustments()) != image.Image(img) {
+		t.Errorf("neutral adjustments copied the image")
+	}
+}
@@ -0,0 +1,190 @@
+package renderer
+
+import "strconv"
+
+// colorKind tells how a termColor is selected
+type colorKind uint8
+
+const (
+	colorDefault colorKind = iota // terminal default color
+	colorBasic                    // one of the 16 basic ANSI colors (stored in r)
+	colorIndexed                  // 256-color palette index (stored in r)
+	colorRGB                      // 24-bit color
+)
+
+// termColor is a foreground or background color as the terminal sees it
+// It is comparable, so cells can be diffed with ==.
+type termColor struct {
+	kind    colorKind
+	r, g, b uint8
+}
+
+// defaultColor selects the terminal's default color
+var defaultColor = termColor{}
+
+// basicColor selects one of the 16 basic ANSI colors
+func basicColor(index int) termColor {
+	return termColor{kind: colorBasic, r: uint8(index)}
+}
+
+// indexedColor selects a 256-color palette entry
+func indexedColor(index int) termColor {
+	return termColor{kind: colorIndexed, r: uint8(index)}
+}
+
+// rgbColor selects a 24-bit color
+func rgbColor(r, g, b uint8) termColor {
+	return termColor{kind: colorRGB, r: r, g: g, b: b}
+}
+
+// Precomputed SGR fragments so the hot path never formats numbers
+var (
+	decimalBytes = newDecimalTable()
+	fg256Bytes   = newSGR256Table("38;5;")
+	bg256Bytes   = newSGR256Table("48;5;")
+	fg16Bytes    = newSGR16Table(30, 90)
+	bg16Bytes    = newSGR16Table(40, 100)
+)
+
+// newDecimalTable returns the decimal strings of 0-255
+func newDecimalTable() [256][]byte {
+	var table [256][]byte
+	for i := range table {
+		table[i] = []byte(strconv.Itoa(i))
+	}
+	return table
+}
+
+// newSGR256Table returns prefix+N for every 256-color index N
+func newSGR256Table(prefix string) [256][]byte {
+	var table [256][]byte
+	for i := range table {
+		table[i] = append([]byte(prefix), decimalBytes[i]...)
+	}
+	return table
+}
+
+// newSGR16Table returns the SGR parameter for each basic color: normal colors
+// start at base, bright colors (8-15) at brightBase
+func newSGR16Table(base, brightBase int) [16][]byte {
+	var table [16][]byte
+	for i := 0; i < 8; i++ {
+		table[i] = decimalBytes[base+i]
+		table[i+8] = decimalBytes[brightBase+i]
+	}
+	return table
+}
+
+// BackgroundSGR returns the SGR parameters selecting rgb as the background at
+// the terminal's color depth, matching it to the 256- or 16-color palette
+// when 24-bit color isn't available
+func BackgroundSGR(rgb [3]uint8, trueColor, color256 bool) string {
+	var c termColor
+	switch {
+	case trueColor:
+		c = rgbColor(rgb[0], rgb[1], rgb[2])
+	case color256:
+		c = indexedColor(rgbToAnsi256(rgb[0], rgb[1], rgb[2]))
+	default:
+		c = basicColor(rgbToAnsi16(rgb[0], rgb[1], rgb[2]))
+	}
+	return string(appendColorParams(nil, c, true))
+}
+
+// ansiWriter builds terminal output while tracking the active colors, so SGR
+// sequences are only written when the foreground or background changes
+type ansiWriter struct {
+	buf []byte
+	fg  termColor
+	bg  termColor
+}
+
+// newAnsiWriter creates a writer with room for roughly size bytes
+// The terminal is assumed to start with default colors.
+func newAnsiWriter(size int) *ansiWriter {
+	return &ansiWriter{buf: make([]byte, 0, size)}
+}
+
+// setColors switches to the given colors, writing one combined SGR sequence
+// covering only the parts that changed
+func (w *ansiWriter) setColors(fg, bg termColor) {
+	if fg == w.fg && bg == w.bg {
+		return
+	}
+
+	w.buf = append(w.buf, "\033["...)
+	separator := false
+	if fg != w.fg {
+		w.buf = appendColorParams(w.buf, fg, false)
+		separator = true
+	}
+	if bg != w.bg {
+		if separator {
+			w.buf = append(w.buf, ';')
+		}
+		w.buf = appendColorParams(w.buf, bg, true)
+	}
+	w.buf = append(w.buf, 'm')
+
+	w.fg, w.bg = fg, bg
+}
+
+// appendColorParams appends the SGR parameters selecting a color
+func appendColorParams(buf []byte, c termColor, background bool) []byte {
+	switch c.kind {
+	case colorBasic:
+		if background {
+			return append(buf, bg16Bytes[c.r]...)
+		}
+		return append(buf, fg16Bytes[c.r]...)
+	case colorIndexed:
+		if background {
+			return append(buf, bg256Bytes[c.r]...)
+		}
+		return append(buf, fg256Bytes[c.r]...)
+	case colorRGB:
+		if background {
+			buf = append(buf, "48;2;"...)
+		} else {
+			buf = append(buf, "38;2;"...)
+		}
+		buf = append(buf, decimalBytes[c.r]...)
+		buf = append(buf, ';')
+		buf = append(buf, decimalBytes[c.g]...)
+		buf = append(buf, ';')
+		return append(buf, decimalBytes[c.b]...)
+	default:
+		if background {
+			return append(buf, "49"...)
+		}
+		return append(buf, "39"...)
+	}
+}
+
+// writeString writes text in the current colors
+func (w *ansiWriter) writeString(s string) {
+	w.buf = append(w.buf, s...)
+}
+
+// moveTo positions the cursor at a 1-based row and column
+func (w *ansiWriter) moveTo(row, col int) {
+	w.buf = append(w.buf, "\033["...)
+	w.buf = strconv.AppendInt(w.buf, int64(row), 10)
+	w.buf = append(w.buf, ';')
+	w.buf = strconv.AppendInt(w.buf, int64(col), 10)
+	w.buf = append(w.buf, 'H')
+}
+
+// reset returns to the default colors if any other color is active
+func (w *ansiWriter) reset() {
+	if w.fg == defaultColor && w.bg == defaultColor {
+		return
+	}
+	w.buf = append(w.buf, "\033[0m"...)
+	w.fg, w.bg = defaultColor, defaultColor
+}
+
+// String returns everything written so far
+func (w *ansiWriter) String() string {
+	return string(w.buf)
+}
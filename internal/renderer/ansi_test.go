@@ -0,0 +1,254 @@
+package renderer
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// screenCell is what a terminal shows in one cell
+type screenCell struct {
+	fg, bg termColor
+	glyph  string
+}
+
+// screen interprets the cursor, color and text output of the text renderers
+type screen struct {
+	cells    map[[2]int]screenCell
+	row, col int
+	fg, bg   termColor
+}
+
+func newScreen() *screen {
+	return &screen{cells: make(map[[2]int]screenCell)}
+}
+
+// write applies terminal output to the screen
+func (s *screen) write(t *testing.T, out string) {
+	for out != "" {
+		switch {
+		case strings.HasPrefix(out, "\033["):
+			end := strings.IndexAny(out, "mH")
+			if end < 0 {
+				t.Fatalf("unterminated escape sequence %q", out)
+			}
+			params := out[2:end]
+			if out[end] == 'H' {
+				row, col, _ := strings.Cut(params, ";")
+				s.row, _ = strconv.Atoi(row)
+				s.col, _ = strconv.Atoi(col)
+				s.row--
+				s.col--
+			} else {
+				s.sgr(t, params)
+			}
+			out = out[end+1:]
+		case out[0] == '\n':
+			s.row++
+			s.col = 0
+			out = out[1:]
+		default:
+			r, size := []rune(out)[0], len(string([]rune(out)[0]))
+			s.cells[[2]int{s.row, s.col}] = screenCell{s.fg, s.bg, string(r)}
+			s.col++
+			out = out[size:]
+		}
+	}
+}
+
+// sgr applies the parameters of a Select Graphic Rendition sequence
+func (s *screen) sgr(t *testing.T, params string) {
+	var values []int
+	for _, field := range strings.Split(params, ";") {
+		value, err := strconv.Atoi(field)
+		if err != nil {
+			t.Fatalf("bad SGR parameter %q in %q", field, params)
+		}
+		values = append(values, value)
+	}
+
+	for i := 0; i < len(values); i++ {
+		v := values[i]
+		switch {
+		case v == 0:
+			s.fg, s.bg = defaultColor, defaultColor
+		case v == 39:
+			s.fg = defaultColor
+		case v == 49:
+			s.bg = defaultColor
+		case v >= 30 && v <= 37:
+			s.fg = basicColor(v - 30)
+		case v >= 90 && v <= 97:
+			s.fg = basicColor(v - 90 + 8)
+		case v >= 40 && v <= 47:
+			s.bg = basicColor(v - 40)
+		case v >= 100 && v <= 107:
+			s.bg = basicColor(v - 100 + 8)
+		case v == 38 || v == 48:
+			var c termColor
+			if values[i+1] == 5 {
+				c = indexedColor(values[i+2])
+				i += 2
+			} else {
+				c = rgbColor(uint8(values[i+2]), uint8(values[i+3]), uint8(values[i+4]))
+				i += 4
+			}
+			if v == 38 {
+				s.fg = c
+			} else {
+				s.bg = c
+			}
+		default:
+			t.Fatalf("unexpected SGR parameter %d in %q", v, params)
+		}
+	}
+}
+
+// perCellSGR writes a frame the way the renderers did before ansiWriter: both
+// colors in full before every cell, and a reset at the end of each row
+func perCellSGR(f *textFrame) string {
+	params := func(c termColor, background bool) string {
+		base := 38
+		if background {
+			base = 48
+		}
+		switch c.kind {
+		case colorBasic:
+			if c.r < 8 {
+				return fmt.Sprintf("%d", base-8+int(c.r))
+			}
+			return fmt.Sprintf("%d", base+52+int(c.r)-8)
+		case colorIndexed:
+			return fmt.Sprintf("%d;5;%d", base, c.r)
+		case colorRGB:
+			return fmt.Sprintf("%d;2;%d;%d;%d", base, c.r, c.g, c.b)
+		default:
+			return fmt.Sprintf("%d", base+1)
+		}
+	}
+
+	var b strings.Builder
+	for y := 0; y < f.height; y++ {
+		for _, c := range f.cells[y*f.width : (y+1)*f.width] {
+			fmt.Fprintf(&b, "\033[%sm\033[%sm%s", params(c.fg, false), params(c.bg, true), c.glyph)
+		}
+		b.WriteString("\033[0m\n")
+	}
+	return b.String()
+}
+
+// testImage returns a small image of flat areas, edges and a gradient
+func testImage(width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.RGBA{uint8(x * 255 / width), uint8(y * 255 / height), 128, 255}
+			if x < width/4 {
+				c = color.RGBA{200, 30, 30, 255}
+			}
+			if (x/3+y/2)%5 == 0 {
+				c = color.RGBA{255, 255, 255, 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestAnsiWriterMatchesPerCellSGR(t *testing.T) {
+	tests := []struct {
+		name      string
+		trueColor bool
+	}{
+		{"24-bit", true},
+		{"256 colors", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewBlocksRenderer(tt.trueColor)
+			frame := r.renderBlocks(testImage(16, 12), 8, 6, 2, 2, quadrantTable)
+
+			want, got := newScreen(), newScreen()
+			want.write(t, perCellSGR(frame))
+			got.write(t, frame.full())
+
+			if len(got.cells) != len(want.cells) {
+				t.Fatalf("ansiWriter drew %d cells, per-cell SGR %d", len(got.cells), len(want.cells))
+			}
+			for position, cell := range want.cells {
+				if got.cells[position] != cell {
+					t.Errorf("cell %v = %+v, want %+v", position, got.cells[position], cell)
+				}
+			}
+			if got.fg != defaultColor || got.bg != defaultColor {
+				t.Errorf("colors left at %+v on %+v, want the defaults", got.fg, got.bg)
+			}
+		})
+	}
+}
+
+func TestAnsiWriterDiffMatchesFullFrame(t *testing.T) {
+	r := NewBlocksRenderer(true)
+	previous := r.renderBlocks(testImage(16, 12), 8, 6, 2, 2, quadrantTable)
+	next := r.renderBlocks(testImage(18, 12), 8, 6, 2, 2, quadrantTable)
+
+	want, got := newScreen(), newScreen()
+	want.write(t, perCellSGR(next))
+	got.write(t, previous.full())
+	got.write(t, next.diff(previous))
+
+	for position, cell := range want.cells {
+		if got.cells[position] != cell {
+			t.Errorf("cell %v = %+v, want %+v", position, got.cells[position], cell)
+		}
+	}
+}
+
+func TestSGRTables(t *testing.T) {
+	for i := 0; i < 256; i++ {
+		if got, want := string(decimalBytes[i]), fmt.Sprintf("%d", i); got != want {
+			t.Errorf("decimalBytes[%d] = %q, want %q", i, got, want)
+		}
+		if got, want := string(fg256Bytes[i]), fmt.Sprintf("38;5;%d", i); got != want {
+			t.Errorf("fg256Bytes[%d] = %q, want %q", i, got, want)
+		}
+		if got, want := string(bg256Bytes[i]), fmt.Sprintf("48;5;%d", i); got != want {
+			t.Errorf("bg256Bytes[%d] = %q, want %q", i, got, want)
+		}
+	}
+
+	for i := 0; i < 16; i++ {
+		fg, bg := 30+i, 40+i
+		if i >= 8 {
+			fg, bg = 90+i-8, 100+i-8
+		}
+		if got, want := string(fg16Bytes[i]), fmt.Sprintf("%d", fg); got != want {
+			t.Errorf("fg16Bytes[%d] = %q, want %q", i, got, want)
+		}
+		if got, want := string(bg16Bytes[i]), fmt.Sprintf("%d", bg); got != want {
+			t.Errorf("bg16Bytes[%d] = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestAnsiWriterSetColors(t *testing.T) {
+	w := newAnsiWriter(0)
+	w.setColors(basicColor(9), defaultColor)
+	w.writeString("a")
+	w.setColors(basicColor(9), defaultColor)
+	w.writeString("b")
+	w.setColors(indexedColor(196), indexedColor(21))
+	w.writeString("c")
+	w.setColors(indexedColor(196), defaultColor)
+	w.reset()
+	w.reset()
+
+	want := "\033[91ma" + "b" + "\033[38;5;196;48;5;21mc" + "\033[49m" + "\033[0m"
+	if got := w.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
@@ -4,22 +4,22 @@ import (
 	"fmt"
 	"image"
 	"image/color"
-	"strings"
 	"terminaltube/pkg/types"
 
 	"github.com/nfnt/resize"
 ) // ASCIIRenderer implements ASCII-based rendering
 type ASCIIRenderer struct {
 	mode        types.RenderMode
-	grayRamp    string
+	rampSource  string   // options.Ramp that grayRamp was split from
+	grayRamp    []string // ramp characters, darkest first
 	initialized bool
+	frames      deltaTracker
 }
 
 // NewASCIIRenderer creates a new ASCII renderer
 func NewASCIIRenderer(mode types.RenderMode) *ASCIIRenderer {
 	return &ASCIIRenderer{
-		mode:     mode,
-		grayRamp: " .:-=+*#%@",
+		mode: mode,
 	}
 }
 
@@ -30,21 +30,28 @@ func (r *ASCIIRenderer) Name() string {
 
 // SupportsMode checks if this renderer supports the given mode
 func (r *ASCIIRenderer) SupportsMode(mode types.RenderMode) bool {
-	return mode == types.ASCII_COLOR || mode == types.ASCII_GRAY
+	return mode == types.ASCII_COLOR || mode == types.ASCII_16 || mode == types.ASCII_GRAY || mode == types.ASCII_SHAPE
 }
 
 // Initialize sets up the renderer
 func (r *ASCIIRenderer) Initialize() error {
 	r.initialized = true
+	r.frames.reset()
 	return nil
 }
 
 // Cleanup performs cleanup
 func (r *ASCIIRenderer) Cleanup() error {
 	r.initialized = false
+	r.frames.reset()
 	return nil
 }
 
+// ResetFrame makes the next delta frame repaint every cell
+func (r *ASCIIRenderer) ResetFrame() {
+	r.frames.reset()
+}
+
 // Render converts an image to ASCII representation
 func (r *ASCIIRenderer) Render(img image.Image, options types.RenderOptions) (string, error) {
 	if !r.initialized {
@@ -57,26 +64,46 @@ func (r *ASCIIRenderer) Render(img image.Image, options types.RenderOptions) (st
 		return "", fmt.Errorf("invalid dimensions: width=%d, height=%d", width, height)
 	}
 
+	if err := r.updateRamp(options.Ramp); err != nil {
+		return "", err
+	}
+
+	// Shape matching samples a small pixel block per cell
+	if r.mode == types.ASCII_SHAPE {
+		resizedImg := resize.Resize(uint(width*shapeCellWidth), uint(height*shapeCellHeight), img, resize.Lanczos3)
+		resizedImg = adjustImage(resizedImg, options.Adjustments)
+		resizedImg = compositeBackground(resizedImg, options)
+		frame := r.renderShapeASCII(resizedImg, width, height, options)
+		return r.frames.encode(frame, options), nil
+	}
+
 	// Resize the image directly to target dimensions
 	// Aspect ratio is already handled by the caller
 	resizedImg := resize.Resize(uint(width), uint(height), img, resize.Lanczos3)
+	resizedImg = adjustImage(resizedImg, options.Adjustments)
+	resizedImg = compositeBackground(resizedImg, options)
 
 	// Convert to ASCII
+	var frame *textFrame
 	switch r.mode {
-	case types.ASCII_COLOR:
-		return r.renderColorASCII(resizedImg, options)
+	case types.ASCII_COLOR, types.ASCII_16:
+		frame = r.renderColorASCII(resizedImg, options)
 	case types.ASCII_GRAY:
-		return r.renderGrayASCII(resizedImg, options)
+		frame = r.renderGrayASCII(resizedImg, options)
 	default:
 		return "", fmt.Errorf("unsupported mode: %s", r.mode.String())
 	}
+
+	return r.frames.encode(frame, options), nil
 }
 
 // renderColorASCII renders using colored ASCII blocks
-func (r *ASCIIRenderer) renderColorASCII(img image.Image, options types.RenderOptions) (string, error) {
+func (r *ASCIIRenderer) renderColorASCII(img image.Image, options types.RenderOptions) *textFrame {
 	bounds := img.Bounds()
-	var result strings.Builder
+	width, height := bounds.Dx(), bounds.Dy()
+	frame := newTextFrame(width, height)
 
+	pixels := make([][3]uint8, 0, width*height)
 	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
 		for x := bounds.Min.X; x < bounds.Max.X; x++ {
 			c := img.At(x, y)
@@ -85,113 +112,138 @@ func (r *ASCIIRenderer) renderColorASCII(img image.Image, options types.RenderOp
 			// Convert to 8-bit values (fix the bit shifting)
 			r8, g8, b8 := uint8(red>>8), uint8(green>>8), uint8(blue>>8)
 
-			// Apply brightness and contrast adjustments
-			r8 = r.adjustPixel(r8, options.Brightness, options.Contrast)
-			g8 = r.adjustPixel(g8, options.Brightness, options.Contrast)
-			b8 = r.adjustPixel(b8, options.Brightness, options.Contrast)
-
-			// Convert to ANSI 256-color
-			ansiColor := r.rgbToAnsi256(r8, g8, b8)
-
-			// Choose character based on brightness for better visibility
-			brightness := (int(r8) + int(g8) + int(b8)) / 3
-			char := "█" // Default block
-			if brightness < 64 {
-				char = "█" // Full block for dark colors
-			} else if brightness < 128 {
-				char = "▓" // Dark shade
-			} else if brightness < 192 {
-				char = "▒" // Medium shade
-			} else {
-				char = "░" // Light shade
-			}
+			pixels = append(pixels, [3]uint8{r8, g8, b8})
+		}
+	}
 
-			// Use foreground color for better contrast
-			result.WriteString(fmt.Sprintf("\033[38;5;%dm%s\033[0m", ansiColor, char))
+	// Convert to ANSI colors; the 256-color cube steps are roughly 40-55 apart,
+	// the 16 basic colors about half the channel range
+	match, spread, toColor := rgbToAnsi256, 51.0, indexedColor
+	if r.mode == types.ASCII_16 {
+		match, spread, toColor = rgbToAnsi16, 128.0, basicColor
+	}
+	colors := ditherPixels(pixels, width, height, options.Dither, spread, func(c [3]uint8) (int, [3]uint8) {
+		index := match(c[0], c[1], c[2])
+		return index, ansi256Palette[index]
+	})
+
+	for i, p := range pixels {
+		// Choose character based on brightness for better visibility
+		brightness := (int(p[0]) + int(p[1]) + int(p[2])) / 3
+		char := "█" // Default block
+		if brightness < 64 {
+			char = "█" // Full block for dark colors
+		} else if brightness < 128 {
+			char = "▓" // Dark shade
+		} else if brightness < 192 {
+			char = "▒" // Medium shade
+		} else {
+			char = "░" // Light shade
 		}
-		result.WriteString("\n")
+
+		// Use foreground color for better contrast
+		frame.set(i%width, i/width, toColor(colors[i]), defaultColor, char)
 	}
 
-	return result.String(), nil
+	return frame
 }
 
 // renderGrayASCII renders using grayscale ASCII characters
-func (r *ASCIIRenderer) renderGrayASCII(img image.Image, options types.RenderOptions) (string, error) {
+func (r *ASCIIRenderer) renderGrayASCII(img image.Image, options types.RenderOptions) *textFrame {
 	bounds := img.Bounds()
-	var result strings.Builder
+	width, height := bounds.Dx(), bounds.Dy()
+	frame := newTextFrame(width, height)
 
+	pixels := make([][3]uint8, 0, width*height)
 	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
 		for x := bounds.Min.X; x < bounds.Max.X; x++ {
 			c := img.At(x, y)
 			gray := color.GrayModel.Convert(c).(color.Gray)
 
-			// Apply brightness and contrast adjustments
-			adjusted := r.adjustPixel(gray.Y, options.Brightness, options.Contrast)
-
-			// Map to character ramp
-			charIndex := int(float64(adjusted) / 255.0 * float64(len(r.grayRamp)-1))
-			if charIndex >= len(r.grayRamp) {
-				charIndex = len(r.grayRamp) - 1
+			adjusted := gray.Y
+			if options.InvertRamp {
+				adjusted = 255 - adjusted
 			}
-
-			result.WriteByte(r.grayRamp[charIndex])
+			pixels = append(pixels, [3]uint8{adjusted, adjusted, adjusted})
 		}
-		result.WriteString("\n")
 	}
 
-	return result.String(), nil
-}
-
-// adjustPixel applies brightness and contrast adjustments
-func (r *ASCIIRenderer) adjustPixel(value uint8, brightness, contrast float64) uint8 {
-	// Apply brightness (additive)
-	adjusted := float64(value) + brightness*255.0
-
-	// Apply contrast (multiplicative around midpoint)
-	adjusted = ((adjusted/255.0-0.5)*contrast + 0.5) * 255.0
+	// Map to character ramp; each character stands for an evenly spaced gray level
+	levels := len(r.grayRamp) - 1
+	step := 255.0 / float64(levels)
+	chars := ditherPixels(pixels, width, height, options.Dither, step, func(c [3]uint8) (int, [3]uint8) {
+		charIndex := int(float64(c[0])/step + 0.5)
+		if charIndex > levels {
+			charIndex = levels
+		}
+		level := clampChannel(float64(charIndex) * step)
+		return charIndex, [3]uint8{level, level, level}
+	})
 
-	// Clamp to valid range
-	if adjusted < 0 {
-		adjusted = 0
-	} else if adjusted > 255 {
-		adjusted = 255
+	for i, charIndex := range chars {
+		frame.set(i%width, i/width, defaultColor, defaultColor, r.grayRamp[charIndex])
 	}
 
-	return uint8(adjusted)
+	return frame
 }
 
-// rgbToAnsi256 converts RGB values to the closest ANSI 256-color code
-func (r *ASCIIRenderer) rgbToAnsi256(red, green, blue uint8) int {
-	// Handle grayscale colors (232-255 are grayscale)
-	if red == green && green == blue {
-		// Map to grayscale range (232-255, 24 levels)
-		if red < 8 {
-			return 16 // Black
-		} else if red > 248 {
-			return 231 // White (standard colors)
-		} else {
-			// Map to grayscale palette (232-255)
-			return 232 + int((red-8)*23/240)
+// renderShapeASCII renders cells with structure as the best-shaped ASCII
+// character and flat cells from the grayscale ramp
+func (r *ASCIIRenderer) renderShapeASCII(img image.Image, width, height int, options types.RenderOptions) *textFrame {
+	bounds := img.Bounds()
+	frame := newTextFrame(width, height)
+	cell := make([]float64, shapeCellWidth*shapeCellHeight)
+
+	for cy := 0; cy < height; cy++ {
+		for cx := 0; cx < width; cx++ {
+			var sum float64
+			for dy := 0; dy < shapeCellHeight; dy++ {
+				for dx := 0; dx < shapeCellWidth; dx++ {
+					c := img.At(bounds.Min.X+cx*shapeCellWidth+dx, bounds.Min.Y+cy*shapeCellHeight+dy)
+					gray := color.GrayModel.Convert(c).(color.Gray)
+					adjusted := gray.Y
+					if options.InvertRamp {
+						// Ink is dark on light backgrounds, so dark pixels carry the shape
+						adjusted = 255 - adjusted
+					}
+					cell[dy*shapeCellWidth+dx] = float64(adjusted) / 255
+					sum += float64(adjusted)
+				}
+			}
+
+			if char, ok := bestShapeGlyph(cell); ok {
+				frame.set(cx, cy, defaultColor, defaultColor, string(char))
+				continue
+			}
+
+			// Flat cell: fall back to the brightness ramp
+			mean := sum / float64(len(cell))
+			frame.set(cx, cy, defaultColor, defaultColor, r.grayRamp[int(mean/255*float64(len(r.grayRamp)-1)+0.5)])
 		}
 	}
 
-	// Convert RGB to 6x6x6 color cube (colors 16-231)
-	// Each component is mapped to 0-5 range with better rounding
-	r6 := int((red*5 + 127) / 255)
-	g6 := int((green*5 + 127) / 255)
-	b6 := int((blue*5 + 127) / 255)
+	return frame
+}
 
-	// Clamp values to valid range
-	if r6 > 5 {
-		r6 = 5
+// updateRamp splits the ramp into characters, reusing the previous split
+// while the ramp is unchanged
+func (r *ASCIIRenderer) updateRamp(ramp string) error {
+	if ramp == "" {
+		ramp = types.RAMP_SHORT.Characters()
 	}
-	if g6 > 5 {
-		g6 = 5
+	if ramp == r.rampSource && r.grayRamp != nil {
+		return nil
 	}
-	if b6 > 5 {
-		b6 = 5
+
+	chars := []rune(ramp)
+	if len(chars) < 2 {
+		return fmt.Errorf("ramp needs at least 2 characters: %q", ramp)
 	}
 
-	// Calculate ANSI color code
-	return 16 + (36 * r6) + (6 * g6) + b6
+	r.grayRamp = make([]string, len(chars))
+	for i, char := range chars {
+		r.grayRamp[i] = string(char)
+	}
+	r.rampSource = ramp
+	return nil
 }
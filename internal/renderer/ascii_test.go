@@ -0,0 +1,82 @@
+package renderer
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"strings"
+	"terminaltube/pkg/types"
+	"testing"
+)
+
+func TestUpdateRamp(t *testing.T) {
+	tests := []struct {
+		name    string
+		ramp    string
+		want    []string
+		wantErr bool
+	}{
+		{"short", types.RAMP_SHORT.Characters(), strings.Split(" .:-=+*#%@", ""), false},
+		{"shades", types.RAMP_SHADES.Characters(), []string{" ", "░", "▒", "▓", "█"}, false},
+		{"blocks", types.RAMP_BLOCKS.Characters(), []string{" ", "▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"}, false},
+		{"empty falls back to short", "", strings.Split(" .:-=+*#%@", ""), false},
+		{"custom", "ab", []string{"a", "b"}, false},
+		{"single character", "#", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewASCIIRenderer(types.ASCII_GRAY)
+			err := r.updateRamp(tt.ramp)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("updateRamp(%q) error = %v, wantErr %v", tt.ramp, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(r.grayRamp, tt.want) {
+				t.Errorf("updateRamp(%q) = %q, want %q", tt.ramp, r.grayRamp, tt.want)
+			}
+		})
+	}
+
+	// The long ramp keeps all 70 characters, darkest first
+	r := NewASCIIRenderer(types.ASCII_GRAY)
+	r.updateRamp(types.RAMP_LONG.Characters())
+	if len(r.grayRamp) != 70 || r.grayRamp[0] != " " || r.grayRamp[69] != "$" {
+		t.Errorf("long ramp = %d characters from %q to %q", len(r.grayRamp), r.grayRamp[0], r.grayRamp[len(r.grayRamp)-1])
+	}
+}
+
+func TestGrayRampInversion(t *testing.T) {
+	// Black, mid-gray and white pixels
+	img := image.NewGray(image.Rect(0, 0, 3, 1))
+	img.SetGray(0, 0, color.Gray{0})
+	img.SetGray(1, 0, color.Gray{128})
+	img.SetGray(2, 0, color.Gray{255})
+
+	tests := []struct {
+		name   string
+		ramp   types.RampPreset
+		invert bool
+		want   string
+	}{
+		{"short on dark background", types.RAMP_SHORT, false, " +@"},
+		{"short on light background", types.RAMP_SHORT, true, "@= "},
+		{"shades on dark background", types.RAMP_SHADES, false, " ▒█"},
+		{"shades on light background", types.RAMP_SHADES, true, "█▒ "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewASCIIRenderer(types.ASCII_GRAY)
+			r.updateRamp(tt.ramp.Characters())
+			frame := r.renderGrayASCII(img, types.RenderOptions{InvertRamp: tt.invert})
+
+			var got strings.Builder
+			for _, cell := range frame.cells {
+				got.WriteString(cell.glyph)
+			}
+			if got.String() != tt.want {
+				t.Errorf("rendered %q, want %q", got.String(), tt.want)
+			}
+		})
+	}
+}
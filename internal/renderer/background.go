@@ -0,0 +1,92 @@
+package renderer
+
+import (
+	"image"
+	"image/draw"
+	"terminaltube/pkg/types"
+)
+
+// Checkerboard shades, the light and dark gray most image editors use
+const (
+	checkerLight = 204
+	checkerDark  = 153
+)
+
+// sixelTransparentAlpha is the alpha below which a pixel is left unset in
+// transparent SIXEL output
+const sixelTransparentAlpha = 128
+
+// compositeBackground flattens transparent pixels onto the background the
+// options select
+// Opaque images are returned untouched, so this is free for video frames.
+func compositeBackground(img image.Image, options types.RenderOptions) image.Image {
+	if o, ok := img.(interface{ Opaque() bool }); ok && o.Opaque() {
+		return img
+	}
+
+	bounds := img.Bounds()
+	src, ok := img.(*image.RGBA)
+	if !ok {
+		src = image.NewRGBA(bounds)
+		draw.Draw(src, bounds, img, bounds.Min, draw.Src)
+	}
+
+	back := options.TerminalBackground
+	if options.Background == types.BACKGROUND_SOLID {
+		back = options.BackgroundColor
+	}
+	checkered := options.Background == types.BACKGROUND_CHECKERBOARD
+
+	// Keep roughly 16 squares across the long side at any output size
+	square := max(2, max(bounds.Dx(), bounds.Dy())/16)
+
+	dst := image.NewRGBA(bounds)
+	for y := 0; y < bounds.Dy(); y++ {
+		srcRow := src.Pix[y*src.Stride : y*src.Stride+bounds.Dx()*4]
+		dstRow := dst.Pix[y*dst.Stride : y*dst.Stride+bounds.Dx()*4]
+		for x := 0; x < bounds.Dx(); x++ {
+			i := x * 4
+			if checkered {
+				shade := uint8(checkerDark)
+				if (x/square+y/square)%2 == 0 {
+					shade = checkerLight
+				}
+				back = [3]uint8{shade, shade, shade}
+			}
+
+			// RGBA is premultiplied, so "over" is just src + back*(1-alpha)
+			inverse := 255 - uint32(srcRow[i+3])
+			for ch := 0; ch < 3; ch++ {
+				dstRow[i+ch] = srcRow[i+ch] + uint8((uint32(back[ch])*inverse+127)/255)
+			}
+			dstRow[i+3] = 255
+		}
+	}
+
+	return dst
+}
+
+// transparentMask marks the pixels of an image below sixelTransparentAlpha,
+// or returns nil if there are none
+func transparentMask(img image.Image) []bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok && o.Opaque() {
+		return nil
+	}
+
+	bounds := img.Bounds()
+	width := bounds.Dx()
+	var mask []bool
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < width; x++ {
+			_, _, _, a := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			if a>>8 >= sixelTransparentAlpha {
+				continue
+			}
+			if mask == nil {
+				mask = make([]bool, width*bounds.Dy())
+			}
+			mask[y*width+x] = true
+		}
+	}
+	return mask
+}
@@ -0,0 +1,106 @@
+package renderer
+
+import (
+	"image"
+	"image/color"
+	"terminaltube/pkg/types"
+	"testing"
+)
+
+// translucentImage returns a 32x4 image of half-transparent red with a fully
+// transparent pixel at (1, 0) and an opaque green one at (3, 0)
+func translucentImage() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 32, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 32; x++ {
+			img.SetRGBA(x, y, color.RGBA{128, 0, 0, 128}) // Premultiplied
+		}
+	}
+	img.SetRGBA(1, 0, color.RGBA{})
+	img.SetRGBA(3, 0, color.RGBA{0, 255, 0, 255})
+	return img
+}
+
+func TestCompositeBackground(t *testing.T) {
+	type pixel struct {
+		x, y int
+		want color.RGBA
+	}
+
+	tests := []struct {
+		name    string
+		options types.RenderOptions
+		pixels  []pixel
+	}{
+		{
+			"solid color",
+			types.RenderOptions{Background: types.BACKGROUND_SOLID, BackgroundColor: [3]uint8{0, 0, 255}},
+			[]pixel{
+				{0, 0, color.RGBA{128, 0, 127, 255}},
+				{1, 0, color.RGBA{0, 0, 255, 255}},
+				{3, 0, color.RGBA{0, 255, 0, 255}},
+				{31, 3, color.RGBA{128, 0, 127, 255}},
+			},
+		},
+		{
+			"terminal background",
+			types.RenderOptions{Background: types.BACKGROUND_TERMINAL, TerminalBackground: [3]uint8{30, 30, 46}},
+			[]pixel{
+				{0, 0, color.RGBA{143, 15, 23, 255}},
+				{1, 0, color.RGBA{30, 30, 46, 255}},
+			},
+		},
+		{
+			// Squares are 2 pixels: 32 pixels across / 16
+			"checkerboard",
+			types.RenderOptions{Background: types.BACKGROUND_CHECKERBOARD},
+			[]pixel{
+				{0, 0, color.RGBA{230, 102, 102, 255}},
+				{1, 0, color.RGBA{checkerLight, checkerLight, checkerLight, 255}},
+				{2, 0, color.RGBA{204, 76, 76, 255}},
+				{3, 0, color.RGBA{0, 255, 0, 255}},
+				{0, 1, color.RGBA{230, 102, 102, 255}},
+				{0, 2, color.RGBA{204, 76, 76, 255}},
+				{2, 2, color.RGBA{230, 102, 102, 255}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			composited := compositeBackground(translucentImage(), tt.options)
+			for _, p := range tt.pixels {
+				if got := color.RGBAModel.Convert(composited.At(p.x, p.y)).(color.RGBA); got != p.want {
+					t.Errorf("pixel (%d, %d) = %v, want %v", p.x, p.y, got, p.want)
+				}
+			}
+		})
+	}
+}
+
+func TestCompositeBackgroundOpaque(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	for i := 3; i < len(img.Pix); i += 4 {
+		img.Pix[i] = 255
+	}
+	options := types.RenderOptions{Background: types.BACKGROUND_CHECKERBOARD}
+	if compositeBackground(img, options) != image.Image(img) {
+		t.Errorf("opaque image was copied")
+	}
+}
+
+func TestTransparentMask(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 3, 1))
+	img.SetRGBA(0, 0, color.RGBA{0, 0, 0, 0})
+	img.SetRGBA(1, 0, color.RGBA{0, 0, 0, sixelTransparentAlpha - 1})
+	img.SetRGBA(2, 0, color.RGBA{0, 0, 0, sixelTransparentAlpha})
+
+	want := []bool{true, true, false}
+	mask := transparentMask(img)
+	for i := range want {
+		if mask[i] != want[i] {
+			t.Errorf("mask = %v, want %v", mask, want)
+			break
+		}
+	}
+}
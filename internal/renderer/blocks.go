@@ -0,0 +1,322 @@
+package renderer
+
+import (
+	"fmt"
+	"image"
+	"terminaltube/pkg/types"
+
+	"github.com/nfnt/resize"
+)
+
+// glyphTable maps a cell's pixel pattern to the character that draws it
+// Pattern bit (y*2 + x) is set when that pixel takes the foreground color.
+// Patterns without their own character are drawn with the glyph of the
+// complementary pattern and the colors swapped (inverted).
+type glyphTable struct {
+	glyphs   []rune
+	inverted []bool
+}
+
+// quadrantGlyphs lists the 2x2 quadrant characters by pattern
+var quadrantGlyphs = []rune(" ▘▝▀▖▌▞▛▗▚▐▜▄▙▟█")
+
+var (
+	quadrantTable = newQuadrantTable()
+	sextantTable  = newSextantTable()
+	octantTable   = newOctantTable()
+)
+
+// newQuadrantTable builds the 2x2 glyph table
+func newQuadrantTable() glyphTable {
+	return glyphTable{
+		glyphs:   quadrantGlyphs,
+		inverted: make([]bool, 16),
+	}
+}
+
+// newSextantTable builds the 2x3 glyph table
+// Sextants U+1FB00-U+1FB3B are numbered in pattern order, skipping the four
+// patterns already covered by space, left half, right half and full block
+func newSextantTable() glyphTable {
+	table := glyphTable{
+		glyphs:   make([]rune, 64),
+		inverted: make([]bool, 64),
+	}
+
+	for pattern := 0; pattern < 64; pattern++ {
+		switch pattern {
+		case 0:
+			table.glyphs[pattern] = ' '
+		case 21:
+			table.glyphs[pattern] = '▌'
+		case 42:
+			table.glyphs[pattern] = '▐'
+		case 63:
+			table.glyphs[pattern] = '█'
+		default:
+			index := pattern - 1
+			if pattern > 21 {
+				index--
+			}
+			if pattern > 42 {
+				index--
+			}
+			table.glyphs[pattern] = rune(0x1FB00 + index)
+		}
+	}
+
+	return table
+}
+
+// newOctantTable builds the 2x4 glyph table
+// Octants U+1CD00-U+1CDE5 are numbered in pattern order, skipping the 26
+// patterns that already have a character elsewhere: the quadrant combinations,
+// the one/three quarter blocks, the four corner eighths and the middle quarters
+func newOctantTable() glyphTable {
+	table := glyphTable{
+		glyphs:   make([]rune, 256),
+		inverted: make([]bool, 256),
+	}
+
+	// Non-quadrant patterns drawn by existing block characters
+	existing := map[int]rune{
+		0x03: '\U0001FB82', // upper one quarter block
+		0x3F: '\U0001FB85', // upper three quarters block
+		0xC0: '▂',          // lower one quarter block
+		0xFC: '▆',          // lower three quarters block
+	}
+	// Excluded patterns whose characters are too new for most fonts;
+	// these are drawn inverted using their complement
+	complemented := map[int]bool{0x01: true, 0x02: true, 0x40: true, 0x80: true, 0x14: true, 0x28: true}
+
+	index := 0
+	for pattern := 0; pattern < 256; pattern++ {
+		if quadrant, ok := octantQuadrant(pattern); ok {
+			table.glyphs[pattern] = quadrantGlyphs[quadrant]
+			continue
+		}
+		if glyph, ok := existing[pattern]; ok {
+			table.glyphs[pattern] = glyph
+			continue
+		}
+		if complemented[pattern] {
+			continue
+		}
+		table.glyphs[pattern] = rune(0x1CD00 + index)
+		index++
+	}
+
+	for pattern := range complemented {
+		table.glyphs[pattern] = table.glyphs[0xFF^pattern]
+		table.inverted[pattern] = true
+	}
+
+	return table
+}
+
+// octantQuadrant reports whether a 2x4 pattern is made of whole 2x2 quadrants
+// and returns the matching quadrant pattern
+func octantQuadrant(pattern int) (int, bool) {
+	quadrantBits := [4][2]int{
+		{0, 2}, // upper left
+		{1, 3}, // upper right
+		{4, 6}, // lower left
+		{5, 7}, // lower right
+	}
+
+	quadrant := 0
+	for i, bits := range quadrantBits {
+		first := pattern >> bits[0] & 1
+		second := pattern >> bits[1] & 1
+		if first != second {
+			return 0, false
+		}
+		quadrant |= first << i
+	}
+	return quadrant, true
+}
+
+// BlocksRenderer implements multi-pixel block rendering
+// Each cell is split into 2x2, 2x3 or 2x4 pixels and drawn with the glyph and
+// foreground/background pair that minimizes color error over those pixels
+type BlocksRenderer struct {
+	initialized bool
+	trueColor   bool
+	frames      deltaTracker
+}
+
+// NewBlocksRenderer creates a new block glyph renderer
+// trueColor selects 24-bit colors, otherwise the 256-color palette is used
+func NewBlocksRenderer(trueColor bool) *BlocksRenderer {
+	return &BlocksRenderer{trueColor: trueColor}
+}
+
+// Name returns the renderer name
+func (r *BlocksRenderer) Name() string {
+	return "Unicode_Blocks"
+}
+
+// SupportsMode checks if this renderer supports the given mode
+func (r *BlocksRenderer) SupportsMode(mode types.RenderMode) bool {
+	return mode == types.BLOCKS
+}
+
+// Initialize sets up the renderer
+func (r *BlocksRenderer) Initialize() error {
+	r.initialized = true
+	r.frames.reset()
+	return nil
+}
+
+// Cleanup performs cleanup
+func (r *BlocksRenderer) Cleanup() error {
+	r.initialized = false
+	r.frames.reset()
+	return nil
+}
+
+// ResetFrame makes the next delta frame repaint every cell
+func (r *BlocksRenderer) ResetFrame() {
+	r.frames.reset()
+}
+
+// Render converts an image to block glyph representation
+func (r *BlocksRenderer) Render(img image.Image, options types.RenderOptions) (string, error) {
+	if !r.initialized {
+		return "", fmt.Errorf("renderer not initialized")
+	}
+
+	width, height := options.Width, options.Height
+
+	if width == 0 || height == 0 {
+		return "", fmt.Errorf("invalid dimensions: width=%d, height=%d", width, height)
+	}
+
+	var table glyphTable
+	switch options.BlockGlyphs {
+	case types.QUADRANTS:
+		table = quadrantTable
+	case types.SEXTANTS:
+		table = sextantTable
+	case types.OCTANTS:
+		table = octantTable
+	default:
+		return "", fmt.Errorf("unsupported glyph set: %s", options.BlockGlyphs)
+	}
+
+	cellWidth, cellHeight := options.BlockGlyphs.CellSize()
+	resizedImg := resize.Resize(uint(width*cellWidth), uint(height*cellHeight), img, resize.Lanczos3)
+	resizedImg = adjustImage(resizedImg, options.Adjustments)
+	resizedImg = compositeBackground(resizedImg, options)
+
+	frame := r.renderBlocks(resizedImg, width, height, cellWidth, cellHeight, table)
+	return r.frames.encode(frame, options), nil
+}
+
+// renderBlocks picks the best glyph and color pair for every cell
+func (r *BlocksRenderer) renderBlocks(img image.Image, width, height, cellWidth, cellHeight int, table glyphTable) *textFrame {
+	bounds := img.Bounds()
+	frame := newTextFrame(width, height)
+
+	cellPixels := cellWidth * cellHeight
+	pixels := make([][3]int, cellPixels)
+
+	for cy := 0; cy < height; cy++ {
+		for cx := 0; cx < width; cx++ {
+			for dy := 0; dy < cellHeight; dy++ {
+				for dx := 0; dx < cellWidth; dx++ {
+					red, green, blue, _ := img.At(bounds.Min.X+cx*cellWidth+dx, bounds.Min.Y+cy*cellHeight+dy).RGBA()
+					pixels[dy*cellWidth+dx] = [3]int{int(red >> 8), int(green >> 8), int(blue >> 8)}
+				}
+			}
+
+			pattern, fg, bg := bestFitCell(pixels)
+
+			glyph := table.glyphs[pattern]
+			if table.inverted[pattern] {
+				fg, bg = bg, fg
+			}
+
+			fgColor, bgColor := r.cellColors(fg, bg)
+			frame.set(cx, cy, fgColor, bgColor, string(glyph))
+		}
+	}
+
+	return frame
+}
+
+// bestFitCell finds the two-color split of a cell with the lowest squared error
+// The foreground takes the pixels whose bits are set in the returned pattern;
+// both colors are the means of the pixels they cover.
+func bestFitCell(pixels [][3]int) (int, [3]uint8, [3]uint8) {
+	count := len(pixels)
+	full := 1<<count - 1
+
+	var total [3]int
+	for _, p := range pixels {
+		total[0] += p[0]
+		total[1] += p[1]
+		total[2] += p[2]
+	}
+
+	// Error is the sum of squares minus |sum|^2/n for each side; the sum of
+	// squares is constant, so maximize the second term. A pattern and its
+	// complement split the same way, so only patterns with bit 0 set are tried.
+	// Start from the uniform split so flat cells stay a single full block.
+	bestPattern := full
+	bestScore := float64(total[0]*total[0]+total[1]*total[1]+total[2]*total[2]) / float64(count)
+	bestFG := total
+	for pattern := 1; pattern < full; pattern += 2 {
+		var fgSum [3]int
+		fgCount := 0
+		for i, p := range pixels {
+			if pattern&(1<<i) != 0 {
+				fgSum[0] += p[0]
+				fgSum[1] += p[1]
+				fgSum[2] += p[2]
+				fgCount++
+			}
+		}
+
+		score := float64(fgSum[0]*fgSum[0]+fgSum[1]*fgSum[1]+fgSum[2]*fgSum[2]) / float64(fgCount)
+		if bgCount := count - fgCount; bgCount > 0 {
+			bg0, bg1, bg2 := total[0]-fgSum[0], total[1]-fgSum[1], total[2]-fgSum[2]
+			score += float64(bg0*bg0+bg1*bg1+bg2*bg2) / float64(bgCount)
+		}
+
+		if score > bestScore {
+			bestScore = score
+			bestPattern = pattern
+			bestFG = fgSum
+		}
+	}
+
+	fgCount := 0
+	for i := 0; i < count; i++ {
+		if bestPattern&(1<<i) != 0 {
+			fgCount++
+		}
+	}
+
+	fg := [3]uint8{uint8(bestFG[0] / fgCount), uint8(bestFG[1] / fgCount), uint8(bestFG[2] / fgCount)}
+	if fgCount == count {
+		// Uniform cell: draw a full block in the average color
+		return bestPattern, fg, fg
+	}
+
+	bgCount := count - fgCount
+	bg := [3]uint8{
+		uint8((total[0] - bestFG[0]) / bgCount),
+		uint8((total[1] - bestFG[1]) / bgCount),
+		uint8((total[2] - bestFG[2]) / bgCount),
+	}
+	return bestPattern, fg, bg
+}
+
+// cellColors converts the foreground and background of a cell to terminal colors
+func (r *BlocksRenderer) cellColors(fg, bg [3]uint8) (termColor, termColor) {
+	if r.trueColor {
+		return rgbColor(fg[0], fg[1], fg[2]), rgbColor(bg[0], bg[1], bg[2])
+	}
+	return indexedColor(rgbToAnsi256(fg[0], fg[1], fg[2])), indexedColor(rgbToAnsi256(bg[0], bg[1], bg[2]))
+}
@@ -0,0 +1,95 @@
+package renderer
+
+import "testing"
+
+func TestSextantTable(t *testing.T) {
+	tests := []struct {
+		pattern int
+		want    rune
+	}{
+		{0, ' '},
+		{1, '\U0001FB00'},
+		{20, '\U0001FB13'},
+		{21, '▌'},
+		{22, '\U0001FB14'},
+		{42, '▐'},
+		{43, '\U0001FB28'},
+		{62, '\U0001FB3B'},
+		{63, '█'},
+	}
+
+	for _, tt := range tests {
+		if got := sextantTable.glyphs[tt.pattern]; got != tt.want {
+			t.Errorf("sextant pattern %d = %U, want %U", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestOctantTable(t *testing.T) {
+	octants := 0
+	for pattern, glyph := range octantTable.glyphs {
+		if glyph == 0 {
+			t.Errorf("octant pattern %#02x has no glyph", pattern)
+		}
+		if octantTable.inverted[pattern] && octantTable.glyphs[0xFF^pattern] != glyph {
+			t.Errorf("inverted octant pattern %#02x doesn't use its complement's glyph", pattern)
+		}
+		if glyph >= 0x1CD00 && glyph <= 0x1CDE5 && !octantTable.inverted[pattern] {
+			octants++
+		}
+	}
+	if octants != 0xE6 {
+		t.Errorf("%d patterns use the octant block, want %d", octants, 0xE6)
+	}
+
+	tests := []struct {
+		pattern int
+		want    rune
+	}{
+		{0x00, ' '},
+		{0x05, '▘'}, // upper left quadrant
+		{0x55, '▌'},
+		{0xFF, '█'},
+		{0x03, '\U0001FB82'},
+		{0xC0, '▂'},
+		{0x04, '\U0001CD00'},
+	}
+	for _, tt := range tests {
+		if got := octantTable.glyphs[tt.pattern]; got != tt.want {
+			t.Errorf("octant pattern %#02x = %U, want %U", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestBestFitCell(t *testing.T) {
+	black, white, red := [3]int{0, 0, 0}, [3]int{255, 255, 255}, [3]int{200, 0, 0}
+
+	tests := []struct {
+		name        string
+		pixels      [][3]int
+		wantPattern int
+		wantFG      [3]uint8
+		wantBG      [3]uint8
+	}{
+		{"flat cell is a full block", [][3]int{red, red, red, red}, 0xF, [3]uint8{200, 0, 0}, [3]uint8{200, 0, 0}},
+		{"left and right halves", [][3]int{black, white, black, white}, 0x5, [3]uint8{0, 0, 0}, [3]uint8{255, 255, 255}},
+		{"top and bottom halves", [][3]int{white, white, black, black}, 0x3, [3]uint8{255, 255, 255}, [3]uint8{0, 0, 0}},
+		{"single corner", [][3]int{red, black, black, black}, 0x1, [3]uint8{200, 0, 0}, [3]uint8{0, 0, 0}},
+		{"corner other than the first", [][3]int{black, black, black, red}, 0x7, [3]uint8{0, 0, 0}, [3]uint8{200, 0, 0}},
+		{
+			"sextant rows",
+			[][3]int{white, white, black, black, black, black},
+			0x03, [3]uint8{255, 255, 255}, [3]uint8{0, 0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, fg, bg := bestFitCell(tt.pixels)
+			if pattern != tt.wantPattern || fg != tt.wantFG || bg != tt.wantBG {
+				t.Errorf("bestFitCell() = %#x, %v, %v; want %#x, %v, %v",
+					pattern, fg, bg, tt.wantPattern, tt.wantFG, tt.wantBG)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package renderer
+
+import (
+	"fmt"
+	"image"
+	"terminaltube/pkg/types"
+
+	"github.com/nfnt/resize"
+)
+
+// brailleDotBits maps a pixel position inside a 2x4 cell to its braille dot bit
+// Indexed as [y][x]; dots 1-3 and 4-6 run down the columns, 7-8 are the bottom row
+var brailleDotBits = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// brailleBase is the empty braille pattern (U+2800)
+const brailleBase = 0x2800
+
+// BrailleRenderer implements sub-cell rendering with Unicode braille patterns
+// Each character cell holds 2x4 dots, giving 8 "pixels" per cell
+type BrailleRenderer struct {
+	initialized bool
+	trueColor   bool
+	frames      deltaTracker
+}
+
+// NewBrailleRenderer creates a new braille renderer
+// trueColor selects 24-bit foreground colors, otherwise the 256-color palette is used
+func NewBrailleRenderer(trueColor bool) *BrailleRenderer {
+	return &BrailleRenderer{trueColor: trueColor}
+}
+
+// Name returns the renderer name
+func (r *BrailleRenderer) Name() string {
+	return "Braille"
+}
+
+// SupportsMode checks if this renderer supports the given mode
+func (r *BrailleRenderer) SupportsMode(mode types.RenderMode) bool {
+	return mode == types.BRAILLE
+}
+
+// Initialize sets up the renderer
+func (r *BrailleRenderer) Initialize() error {
+	r.initialized = true
+	r.frames.reset()
+	return nil
+}
+
+// Cleanup performs cleanup
+func (r *BrailleRenderer) Cleanup() error {
+	r.initialized = false
+	r.frames.reset()
+	return nil
+}
+
+// ResetFrame makes the next delta frame repaint every cell
+func (r *BrailleRenderer) ResetFrame() {
+	r.frames.reset()
+}
+
+// Render converts an image to braille pattern representation
+func (r *BrailleRenderer) Render(img image.Image, options types.RenderOptions) (string, error) {
+	if !r.initialized {
+		return "", fmt.Errorf("renderer not initialized")
+	}
+
+	width, height := options.Width, options.Height
+
+	if width == 0 || height == 0 {
+		return "", fmt.Errorf("invalid dimensions: width=%d, height=%d", width, height)
+	}
+
+	// Each character covers 2 pixels horizontally and 4 vertically
+	pixelWidth := width * 2
+	pixelHeight := height * 4
+	resizedImg := resize.Resize(uint(pixelWidth), uint(pixelHeight), img, resize.Lanczos3)
+	resizedImg = adjustImage(resizedImg, options.Adjustments)
+	resizedImg = compositeBackground(resizedImg, options)
+
+	frame := r.renderBraille(resizedImg, width, height, options)
+	return r.frames.encode(frame, options), nil
+}
+
+// renderBraille decides which dots are lit and assembles the braille characters
+func (r *BrailleRenderer) renderBraille(img image.Image, width, height int, options types.RenderOptions) *textFrame {
+	bounds := img.Bounds()
+	pixelWidth := width * 2
+	pixelHeight := height * 4
+
+	// Gather colors and luminance once
+	colors := make([][3]uint8, pixelWidth*pixelHeight)
+	luma := make([]float64, pixelWidth*pixelHeight)
+	var lumaSum float64
+	for y := 0; y < pixelHeight; y++ {
+		for x := 0; x < pixelWidth; x++ {
+			red, green, blue, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			i := y*pixelWidth + x
+			colors[i] = [3]uint8{uint8(red >> 8), uint8(green >> 8), uint8(blue >> 8)}
+			luma[i] = 0.299*float64(colors[i][0]) + 0.587*float64(colors[i][1]) + 0.114*float64(colors[i][2])
+			lumaSum += luma[i]
+		}
+	}
+
+	// Threshold defaults to the mean brightness so dark and bright images both keep detail
+	threshold := float64(options.BrailleThreshold)
+	if threshold <= 0 {
+		threshold = lumaSum / float64(len(luma))
+	}
+
+	// Dots are a two-level palette: off (black) or lit (white)
+	gray := make([][3]uint8, len(luma))
+	for i, l := range luma {
+		v := clampChannel(l)
+		gray[i] = [3]uint8{v, v, v}
+	}
+	dots := ditherPixels(gray, pixelWidth, pixelHeight, options.Dither, 255, func(c [3]uint8) (int, [3]uint8) {
+		if float64(c[0]) >= threshold {
+			return 1, [3]uint8{255, 255, 255}
+		}
+		return 0, [3]uint8{}
+	})
+
+	frame := newTextFrame(width, height)
+
+	for cy := 0; cy < height; cy++ {
+		for cx := 0; cx < width; cx++ {
+			pattern := rune(0)
+			var sumR, sumG, sumB, count int
+			for dy := 0; dy < 4; dy++ {
+				for dx := 0; dx < 2; dx++ {
+					i := (cy*4+dy)*pixelWidth + cx*2 + dx
+					if dots[i] == 1 {
+						pattern |= brailleDotBits[dy][dx]
+						sumR += int(colors[i][0])
+						sumG += int(colors[i][1])
+						sumB += int(colors[i][2])
+						count++
+					}
+				}
+			}
+
+			if pattern == 0 {
+				frame.set(cx, cy, defaultColor, defaultColor, " ")
+				continue
+			}
+
+			fg := defaultColor
+			if options.BrailleColor {
+				red, green, blue := uint8(sumR/count), uint8(sumG/count), uint8(sumB/count)
+				if r.trueColor {
+					fg = rgbColor(red, green, blue)
+				} else {
+					fg = indexedColor(rgbToAnsi256(red, green, blue))
+				}
+			}
+			frame.set(cx, cy, fg, defaultColor, string(brailleBase+pattern))
+		}
+	}
+
+	return frame
+}
@@ -0,0 +1,137 @@
+package renderer
+
+import "terminaltube/pkg/types"
+
+// deltaMergeGap is the longest run of unchanged cells that is repainted
+// instead of jumping over it; a cursor-positioning escape costs about as much
+const deltaMergeGap = 4
+
+// FrameTracker is implemented by renderers that can emit delta frames
+// (see RenderOptions.DeltaFrames). ResetFrame must be called whenever the
+// screen is cleared behind the renderer's back so the next frame repaints fully.
+type FrameTracker interface {
+	ResetFrame()
+}
+
+// textCell is one character cell of a text-mode frame
+type textCell struct {
+	fg, bg termColor
+	glyph  string
+}
+
+// textFrame is a grid of cells produced by a text renderer
+type textFrame struct {
+	width, height int
+	row, col      int // 0-based screen position of the top-left cell
+	cells         []textCell
+}
+
+// newTextFrame creates an empty frame of the given size
+func newTextFrame(width, height int) *textFrame {
+	return &textFrame{
+		width:  width,
+		height: height,
+		cells:  make([]textCell, width*height),
+	}
+}
+
+// set stores the colors and glyph of the cell at (x, y)
+func (f *textFrame) set(x, y int, fg, bg termColor, glyph string) {
+	f.cells[y*f.width+x] = textCell{fg: fg, bg: bg, glyph: glyph}
+}
+
+// deltaTracker remembers the last frame a text renderer emitted
+type deltaTracker struct {
+	previous *textFrame
+}
+
+// reset forgets the previous frame so the next encode repaints everything
+func (t *deltaTracker) reset() {
+	t.previous = nil
+}
+
+// encode converts a frame to terminal output, placed at the options' origin
+// With DeltaFrames set and a previous frame of the same size and position,
+// only the runs of changed cells are written, each positioned absolutely;
+// otherwise the full frame is written row by row.
+func (t *deltaTracker) encode(frame *textFrame, options types.RenderOptions) string {
+	frame.row, frame.col = options.OriginRow, options.OriginColumn
+
+	previous := t.previous
+	if options.DeltaFrames {
+		t.previous = frame
+	} else {
+		t.previous = nil
+	}
+
+	if !options.DeltaFrames || previous == nil ||
+		previous.width != frame.width || previous.height != frame.height ||
+		previous.row != frame.row || previous.col != frame.col {
+		return frame.full()
+	}
+	return frame.diff(previous)
+}
+
+// full writes every cell of the frame
+// Frames at the origin flow from the cursor line by line; placed frames
+// position each row instead.
+func (f *textFrame) full() string {
+	w := newAnsiWriter(f.width * f.height * 8)
+	placed := f.row != 0 || f.col != 0
+
+	for y := 0; y < f.height; y++ {
+		if placed {
+			w.moveTo(f.row+y+1, f.col+1)
+		}
+		for _, c := range f.cells[y*f.width : (y+1)*f.width] {
+			w.setColors(c.fg, c.bg)
+			w.writeString(c.glyph)
+		}
+		// Reset before the newline so a scroll doesn't fill with the background color
+		w.reset()
+		if !placed {
+			w.writeString("\n")
+		}
+	}
+
+	return w.String()
+}
+
+// diff writes the runs of cells that differ from the previous frame
+func (f *textFrame) diff(previous *textFrame) string {
+	w := newAnsiWriter(f.width * 8)
+
+	for y := 0; y < f.height; y++ {
+		row := f.cells[y*f.width : (y+1)*f.width]
+		previousRow := previous.cells[y*f.width : (y+1)*f.width]
+
+		for x := 0; x < f.width; {
+			if row[x] == previousRow[x] {
+				x++
+				continue
+			}
+
+			// Extend the run, swallowing short stretches of unchanged cells
+			end, unchanged := x+1, 0
+			for i := x + 1; i < f.width && unchanged <= deltaMergeGap; i++ {
+				if row[i] == previousRow[i] {
+					unchanged++
+				} else {
+					unchanged = 0
+					end = i + 1
+				}
+			}
+
+			// Colors carry over between runs; moving the cursor paints nothing
+			w.moveTo(f.row+y+1, f.col+x+1)
+			for _, c := range row[x:end] {
+				w.setColors(c.fg, c.bg)
+				w.writeString(c.glyph)
+			}
+			x = end
+		}
+	}
+	w.reset()
+
+	return w.String()
+}
@@ -0,0 +1,122 @@
+package renderer
+
+import (
+	"terminaltube/pkg/types"
+	"testing"
+)
+
+// testFrame returns a frame of red glyphs, one row per string
+func testFrame(rows ...string) *textFrame {
+	frame := newTextFrame(len(rows[0]), len(rows))
+	for y, row := range rows {
+		for x, glyph := range row {
+			frame.set(x, y, basicColor(1), defaultColor, string(glyph))
+		}
+	}
+	return frame
+}
+
+func TestDeltaFrames(t *testing.T) {
+	tests := []struct {
+		name            string
+		previous        *textFrame
+		previousOptions types.RenderOptions
+		next            *textFrame
+		nextOptions     types.RenderOptions
+		want            string
+	}{
+		{
+			"identical frames",
+			testFrame("aaaaaaaa", "aaaaaaaa"), types.RenderOptions{DeltaFrames: true},
+			testFrame("aaaaaaaa", "aaaaaaaa"), types.RenderOptions{DeltaFrames: true},
+			"",
+		},
+		{
+			"one changed cell",
+			testFrame("aaaaaaaa", "aaaaaaaa"), types.RenderOptions{DeltaFrames: true},
+			testFrame("aaaaaaaa", "aaabaaaa"), types.RenderOptions{DeltaFrames: true},
+			"\033[2;4H\033[31mb\033[0m",
+		},
+		{
+			"runs within deltaMergeGap are merged",
+			testFrame("aaaaaaaa", "aaaaaaaa"), types.RenderOptions{DeltaFrames: true},
+			testFrame("abaabaaa", "aaaaaaaa"), types.RenderOptions{DeltaFrames: true},
+			"\033[1;2H\033[31mbaab\033[0m",
+		},
+		{
+			"runs further apart are positioned separately",
+			testFrame("aaaaaaaa", "aaaaaaaa"), types.RenderOptions{DeltaFrames: true},
+			testFrame("baaaaaba", "aaaaaaaa"), types.RenderOptions{DeltaFrames: true},
+			"\033[1;1H\033[31mb\033[1;7Hb\033[0m",
+		},
+		{
+			"placed frame offsets the runs",
+			testFrame("aaaa"), types.RenderOptions{DeltaFrames: true, OriginRow: 2, OriginColumn: 5},
+			testFrame("aaba"), types.RenderOptions{DeltaFrames: true, OriginRow: 2, OriginColumn: 5},
+			"\033[3;8H\033[31mb\033[0m",
+		},
+		{
+			"changed size repaints everything",
+			testFrame("aaaa"), types.RenderOptions{DeltaFrames: true},
+			testFrame("aaa", "aaa"), types.RenderOptions{DeltaFrames: true},
+			"\033[31maaa\033[0m\n\033[31maaa\033[0m\n",
+		},
+		{
+			"changed origin repaints everything",
+			testFrame("aaaa", "aaaa"), types.RenderOptions{DeltaFrames: true},
+			testFrame("aaaa", "aaaa"), types.RenderOptions{DeltaFrames: true, OriginRow: 1, OriginColumn: 2},
+			"\033[2;3H\033[31maaaa\033[0m\033[3;3H\033[31maaaa\033[0m",
+		},
+		{
+			"delta frames off",
+			testFrame("aaaa"), types.RenderOptions{},
+			testFrame("aaaa"), types.RenderOptions{},
+			"\033[31maaaa\033[0m\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tracker deltaTracker
+			tracker.encode(tt.previous, tt.previousOptions)
+			if got := tracker.encode(tt.next, tt.nextOptions); got != tt.want {
+				t.Errorf("encode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeltaFrameColorChange(t *testing.T) {
+	var tracker deltaTracker
+	options := types.RenderOptions{DeltaFrames: true}
+	tracker.encode(testFrame("aaaa"), options)
+
+	next := testFrame("aaaa")
+	next.set(1, 0, basicColor(1), rgbColor(0, 0, 255), "a")
+	next.set(2, 0, basicColor(2), rgbColor(0, 0, 255), "a")
+
+	want := "\033[1;2H\033[31;48;2;0;0;255ma\033[32ma\033[0m"
+	if got := tracker.encode(next, options); got != want {
+		t.Errorf("encode() = %q, want %q", got, want)
+	}
+}
+
+func TestResetFrame(t *testing.T) {
+	options := types.RenderOptions{DeltaFrames: true}
+	want := "\033[31maaaa\033[0m\n"
+
+	var tracker deltaTracker
+	tracker.encode(testFrame("aaaa"), options)
+	tracker.reset()
+	if got := tracker.encode(testFrame("aaaa"), options); got != want {
+		t.Errorf("encode() after reset = %q, want %q", got, want)
+	}
+
+	// The renderers reset their own tracker
+	r := NewBlocksRenderer(true)
+	r.frames.encode(testFrame("aaaa"), options)
+	r.ResetFrame()
+	if r.frames.previous != nil {
+		t.Errorf("ResetFrame kept the previous frame")
+	}
+}
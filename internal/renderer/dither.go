@@ -0,0 +1,249 @@
+package renderer
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"terminaltube/pkg/types"
+)
+
+// quantizeFunc maps a color to a palette index and the palette color it stands for
+type quantizeFunc func(c [3]uint8) (int, [3]uint8)
+
+// diffusionTap is one neighbour receiving a share of the quantization error
+type diffusionTap struct {
+	dx, dy int
+	weight float64
+}
+
+var (
+	floydSteinbergTaps = []diffusionTap{
+		{1, 0, 7.0 / 16}, {-1, 1, 3.0 / 16}, {0, 1, 5.0 / 16}, {1, 1, 1.0 / 16},
+	}
+	// Atkinson only spreads 6/8 of the error, which keeps highlights and shadows clean
+	atkinsonTaps = []diffusionTap{
+		{1, 0, 1.0 / 8}, {2, 0, 1.0 / 8}, {-1, 1, 1.0 / 8}, {0, 1, 1.0 / 8}, {1, 1, 1.0 / 8}, {0, 2, 1.0 / 8},
+	}
+)
+
+// ditherPixels quantizes a width x height grid of colors with the given dither mode
+// spread is the typical distance between neighbouring palette colors; ordered
+// modes offset each pixel by up to half of it before quantizing
+func ditherPixels(pixels [][3]uint8, width, height int, mode types.DitherMode, spread float64, quantize quantizeFunc) []int {
+	indices := make([]int, len(pixels))
+
+	switch mode {
+	case types.DITHER_FLOYD_STEINBERG:
+		diffuseError(pixels, indices, width, height, floydSteinbergTaps, quantize)
+	case types.DITHER_ATKINSON:
+		diffuseError(pixels, indices, width, height, atkinsonTaps, quantize)
+	case types.DITHER_BAYER4, types.DITHER_BAYER8, types.DITHER_BLUE_NOISE:
+		thresholds, size := thresholdMap(mode)
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				offset := (thresholds[(y%size)*size+x%size] - 0.5) * spread
+				c := pixels[y*width+x]
+				indices[y*width+x], _ = quantize([3]uint8{
+					clampChannel(float64(c[0]) + offset),
+					clampChannel(float64(c[1]) + offset),
+					clampChannel(float64(c[2]) + offset),
+				})
+			}
+		}
+	default:
+		for i, c := range pixels {
+			indices[i], _ = quantize(c)
+		}
+	}
+
+	return indices
+}
+
+// diffuseError quantizes pixels left to right, pushing each pixel's error onto its neighbours
+func diffuseError(pixels [][3]uint8, indices []int, width, height int, taps []diffusionTap, quantize quantizeFunc) {
+	work := make([][3]float64, len(pixels))
+	for i, c := range pixels {
+		work[i] = [3]float64{float64(c[0]), float64(c[1]), float64(c[2])}
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			i := y*width + x
+			current := [3]uint8{clampChannel(work[i][0]), clampChannel(work[i][1]), clampChannel(work[i][2])}
+
+			var chosen [3]uint8
+			indices[i], chosen = quantize(current)
+
+			errR := work[i][0] - float64(chosen[0])
+			errG := work[i][1] - float64(chosen[1])
+			errB := work[i][2] - float64(chosen[2])
+
+			for _, tap := range taps {
+				nx, ny := x+tap.dx, y+tap.dy
+				if nx < 0 || nx >= width || ny >= height {
+					continue
+				}
+				n := ny*width + nx
+				work[n][0] += errR * tap.weight
+				work[n][1] += errG * tap.weight
+				work[n][2] += errB * tap.weight
+			}
+		}
+	}
+}
+
+// clampChannel rounds and clamps a channel value to 0-255
+func clampChannel(v float64) uint8 {
+	if v <= 0 {
+		return 0
+	}
+	if v >= 255 {
+		return 255
+	}
+	return uint8(v + 0.5)
+}
+
+var (
+	bayer4Map    = bayerMatrix(4)
+	bayer8Map    = bayerMatrix(8)
+	blueNoiseMap []float64
+	blueNoiseGen sync.Once
+)
+
+// blueNoiseSize is the side length of the generated blue-noise tile
+const blueNoiseSize = 64
+
+// thresholdMap returns the ordered-dither threshold tile (values in [0,1)) and its size
+func thresholdMap(mode types.DitherMode) ([]float64, int) {
+	switch mode {
+	case types.DITHER_BAYER8:
+		return bayer8Map, 8
+	case types.DITHER_BLUE_NOISE:
+		blueNoiseGen.Do(func() {
+			blueNoiseMap = generateBlueNoise(blueNoiseSize)
+		})
+		return blueNoiseMap, blueNoiseSize
+	default:
+		return bayer4Map, 4
+	}
+}
+
+// bayerMatrix builds a normalized size x size Bayer matrix (size must be a power of two)
+func bayerMatrix(size int) []float64 {
+	ranks := []int{0}
+	for n := 1; n < size; n *= 2 {
+		next := make([]int, 4*n*n)
+		for y := 0; y < n; y++ {
+			for x := 0; x < n; x++ {
+				v := 4 * ranks[y*n+x]
+				next[y*2*n+x] = v
+				next[y*2*n+x+n] = v + 2
+				next[(y+n)*2*n+x] = v + 3
+				next[(y+n)*2*n+x+n] = v + 1
+			}
+		}
+		ranks = next
+	}
+
+	thresholds := make([]float64, len(ranks))
+	for i, rank := range ranks {
+		thresholds[i] = (float64(rank) + 0.5) / float64(len(ranks))
+	}
+	return thresholds
+}
+
+// generateBlueNoise builds a size x size blue-noise threshold map using the
+// void-and-cluster method: pixels are ranked by how clustered they are under
+// a toroidal Gaussian filter, so thresholds are spread evenly at every level
+func generateBlueNoise(size int) []float64 {
+	n := size * size
+	const sigma = 1.5
+
+	// Precompute the toroidal Gaussian kernel by offset
+	kernel := make([]float64, n)
+	for dy := 0; dy < size; dy++ {
+		for dx := 0; dx < size; dx++ {
+			wx := math.Min(float64(dx), float64(size-dx))
+			wy := math.Min(float64(dy), float64(size-dy))
+			kernel[dy*size+dx] = math.Exp(-(wx*wx + wy*wy) / (2 * sigma * sigma))
+		}
+	}
+
+	energy := make([]float64, n)
+	pattern := make([]bool, n)
+	toggle := func(p int, on bool) {
+		pattern[p] = on
+		sign := 1.0
+		if !on {
+			sign = -1.0
+		}
+		px, py := p%size, p/size
+		for y := 0; y < size; y++ {
+			row := ((y - py + size) % size) * size
+			for x := 0; x < size; x++ {
+				energy[y*size+x] += sign * kernel[row+(x-px+size)%size]
+			}
+		}
+	}
+	extreme := func(want bool, largest bool) int {
+		best := -1
+		for i := 0; i < n; i++ {
+			if pattern[i] != want {
+				continue
+			}
+			if best < 0 || (largest && energy[i] > energy[best]) || (!largest && energy[i] < energy[best]) {
+				best = i
+			}
+		}
+		return best
+	}
+
+	// Initial random pattern with ~10% of pixels set (fixed seed for a stable map)
+	rng := rand.New(rand.NewSource(1))
+	initial := n / 10
+	for placed := 0; placed < initial; {
+		p := rng.Intn(n)
+		if !pattern[p] {
+			toggle(p, true)
+			placed++
+		}
+	}
+
+	// Move points from the tightest cluster to the largest void until stable
+	for {
+		cluster := extreme(true, true)
+		toggle(cluster, false)
+		void := extreme(false, false)
+		if void == cluster {
+			toggle(cluster, true)
+			break
+		}
+		toggle(void, true)
+	}
+
+	ranks := make([]int, n)
+	prototype := append([]bool(nil), pattern...)
+	prototypeEnergy := append([]float64(nil), energy...)
+
+	// Phase 1: rank the initial points by removing the tightest cluster each step
+	for rank := initial - 1; rank >= 0; rank-- {
+		cluster := extreme(true, true)
+		toggle(cluster, false)
+		ranks[cluster] = rank
+	}
+
+	// Phase 2: restore the prototype and fill the largest void each step
+	copy(pattern, prototype)
+	copy(energy, prototypeEnergy)
+	for rank := initial; rank < n; rank++ {
+		void := extreme(false, false)
+		toggle(void, true)
+		ranks[void] = rank
+	}
+
+	thresholds := make([]float64, n)
+	for i, rank := range ranks {
+		thresholds[i] = (float64(rank) + 0.5) / float64(n)
+	}
+	return thresholds
+}
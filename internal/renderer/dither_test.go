@@ -0,0 +1,112 @@
+package renderer
+
+import (
+	"math"
+	"terminaltube/pkg/types"
+	"testing"
+)
+
+// blackOrWhite quantizes to a two-color palette: 0 black, 1 white
+func blackOrWhite(c [3]uint8) (int, [3]uint8) {
+	if int(c[0])+int(c[1])+int(c[2]) >= 3*128 {
+		return 1, [3]uint8{255, 255, 255}
+	}
+	return 0, [3]uint8{0, 0, 0}
+}
+
+// flatPixels returns width x height pixels of one gray level
+func flatPixels(width, height int, level uint8) [][3]uint8 {
+	pixels := make([][3]uint8, width*height)
+	for i := range pixels {
+		pixels[i] = [3]uint8{level, level, level}
+	}
+	return pixels
+}
+
+func TestDitherFlatGray(t *testing.T) {
+	const size = 64
+
+	// A quarter-gray should come out about a quarter white; Atkinson drops
+	// a quarter of the error, so it lands lower
+	tests := []struct {
+		mode      types.DitherMode
+		wantWhite float64
+		tolerance float64
+	}{
+		{types.DITHER_NONE, 0, 0},
+		{types.DITHER_BAYER4, 0.25, 0},
+		{types.DITHER_BAYER8, 0.25, 0},
+		{types.DITHER_BLUE_NOISE, 0.25, 0.005},
+		{types.DITHER_FLOYD_STEINBERG, 0.25, 0.01},
+		{types.DITHER_ATKINSON, 0.18, 0.02},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			indices := ditherPixels(flatPixels(size, size, 64), size, size, tt.mode, 255, blackOrWhite)
+
+			white := 0
+			for _, index := range indices {
+				white += index
+			}
+			if share := float64(white) / float64(len(indices)); math.Abs(share-tt.wantWhite) > tt.tolerance {
+				t.Errorf("%.3f of the pixels are white, want %.3f ± %.3f", share, tt.wantWhite, tt.tolerance)
+			}
+		})
+	}
+}
+
+func TestDitherNoneKeepsColors(t *testing.T) {
+	const width, height = 16, 4
+	pixels := make([][3]uint8, width*height)
+	for i := range pixels {
+		pixels[i] = [3]uint8{uint8(i * 4), uint8(i * 4), uint8(i * 4)}
+	}
+	// Each gray level is its own palette entry
+	identity := func(c [3]uint8) (int, [3]uint8) { return int(c[0]), c }
+
+	indices := ditherPixels(pixels, width, height, types.DITHER_NONE, 32, identity)
+	for i, index := range indices {
+		if index != int(pixels[i][0]) {
+			t.Errorf("pixel %d quantized to %d, want %d", i, index, pixels[i][0])
+		}
+	}
+}
+
+func TestThresholdMapsArePermutations(t *testing.T) {
+	tests := []struct {
+		name       string
+		thresholds []float64
+		size       int
+	}{
+		{"Bayer 4x4", bayerMatrix(4), 4},
+		{"Bayer 8x8", bayerMatrix(8), 8},
+		{"blue noise", generateBlueNoise(16), 16},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := tt.size * tt.size
+			if len(tt.thresholds) != n {
+				t.Fatalf("got %d thresholds, want %d", len(tt.thresholds), n)
+			}
+
+			seen := make([]bool, n)
+			for i, threshold := range tt.thresholds {
+				rank := int(math.Round(threshold*float64(n) - 0.5))
+				if rank < 0 || rank >= n || seen[rank] {
+					t.Fatalf("threshold %d = %v is not a distinct rank in 0..%d", i, threshold, n-1)
+				}
+				seen[rank] = true
+			}
+		})
+	}
+
+	// The classic 4x4 ordering
+	want := []int{0, 8, 2, 10, 12, 4, 14, 6, 3, 11, 1, 9, 15, 7, 13, 5}
+	for i, threshold := range bayerMatrix(4) {
+		if rank := int(math.Round(threshold*16 - 0.5)); rank != want[i] {
+			t.Errorf("Bayer 4x4 entry %d = %d, want %d", i, rank, want[i])
+		}
+	}
+}
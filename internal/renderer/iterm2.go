@@ -0,0 +1,115 @@
+package renderer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"strings"
+	"terminaltube/pkg/types"
+
+	"github.com/nfnt/resize"
+)
+
+// ITerm2Renderer implements the iTerm2 inline image protocol (OSC 1337 File=)
+// Each frame is sent as a PNG sized in character cells, so colors are exact
+// and the terminal does the final scaling
+type ITerm2Renderer struct {
+	initialized bool
+	encoder     png.Encoder
+	buffer      bytes.Buffer
+}
+
+// NewITerm2Renderer creates a new iTerm2 inline image renderer
+func NewITerm2Renderer() *ITerm2Renderer {
+	return &ITerm2Renderer{}
+}
+
+// Name returns the renderer name
+func (r *ITerm2Renderer) Name() string {
+	return "iTerm2_Inline"
+}
+
+// SupportsMode checks if this renderer supports the given mode
+func (r *ITerm2Renderer) SupportsMode(mode types.RenderMode) bool {
+	return mode == types.ITERM2
+}
+
+// Initialize sets up the renderer
+func (r *ITerm2Renderer) Initialize() error {
+	// Favour encode speed over size - frames are sent many times per second
+	r.encoder = png.Encoder{
+		CompressionLevel: png.BestSpeed,
+		BufferPool:       &pngBufferPool{},
+	}
+	r.initialized = true
+	return nil
+}
+
+// Cleanup performs cleanup
+func (r *ITerm2Renderer) Cleanup() error {
+	r.buffer = bytes.Buffer{}
+	r.initialized = false
+	return nil
+}
+
+// Render converts an image to an OSC 1337 inline image sequence
+func (r *ITerm2Renderer) Render(img image.Image, options types.RenderOptions) (string, error) {
+	if !r.initialized {
+		return "", fmt.Errorf("renderer not initialized")
+	}
+
+	cols, rows := options.Width, options.Height
+	if cols == 0 || rows == 0 {
+		return "", fmt.Errorf("invalid dimensions: width=%d, height=%d", cols, rows)
+	}
+
+	// Don't send more pixels than the cell area can show
+	targetWidth, targetHeight := FramePixelSize(options)
+	bounds := img.Bounds()
+	if bounds.Dx() > targetWidth || bounds.Dy() > targetHeight {
+		img = resize.Resize(uint(targetWidth), uint(targetHeight), img, resize.Bilinear)
+	}
+
+	img = adjustImage(img, options.Adjustments)
+
+	// The terminal blends real alpha itself, so only flatten onto explicit backgrounds
+	if options.Background == types.BACKGROUND_SOLID || options.Background == types.BACKGROUND_CHECKERBOARD {
+		img = compositeBackground(img, options)
+	}
+
+	r.buffer.Reset()
+	if err := r.encoder.Encode(&r.buffer, img); err != nil {
+		return "", fmt.Errorf("failed to encode frame: %w", err)
+	}
+
+	payload := base64.StdEncoding.EncodeToString(r.buffer.Bytes())
+
+	var sb strings.Builder
+	sb.Grow(len(payload) + 96)
+
+	// Width and height without units are in character cells. Aspect is already
+	// handled by the caller, so let the terminal fill exactly that area.
+	sb.WriteString(fmt.Sprintf("\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=0:",
+		r.buffer.Len(), cols, rows))
+	sb.WriteString(payload)
+	sb.WriteString("\a")
+
+	return graphicsOutput(options, sb.String()), nil
+}
+
+// pngBufferPool reuses the PNG encoder's scratch buffers between frames
+type pngBufferPool struct {
+	buffer *png.EncoderBuffer
+}
+
+// Get returns the cached encoder buffer
+func (p *pngBufferPool) Get() *png.EncoderBuffer {
+	return p.buffer
+}
+
+// Put stores the encoder buffer for the next frame
+func (p *pngBufferPool) Put(buffer *png.EncoderBuffer) {
+	p.buffer = buffer
+}
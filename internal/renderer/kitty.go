@@ -0,0 +1,140 @@
+package renderer
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/draw"
+	"os"
+	"strings"
+	"terminaltube/pkg/types"
+
+	"github.com/nfnt/resize"
+)
+
+// kittyChunkSize is the maximum base64 payload per escape sequence allowed by the protocol
+const kittyChunkSize = 4096
+
+// KittyRenderer implements the Kitty terminal graphics protocol
+// Frames are sent as zlib-compressed RGBA under a stable image ID and placement ID,
+// so every new frame replaces the previous one in place instead of stacking up
+type KittyRenderer struct {
+	initialized bool
+	imageID     uint32
+}
+
+// NewKittyRenderer creates a new Kitty graphics renderer
+func NewKittyRenderer() *KittyRenderer {
+	return &KittyRenderer{}
+}
+
+// Name returns the renderer name
+func (r *KittyRenderer) Name() string {
+	return "Kitty_Graphics"
+}
+
+// SupportsMode checks if this renderer supports the given mode
+func (r *KittyRenderer) SupportsMode(mode types.RenderMode) bool {
+	return mode == types.KITTY
+}
+
+// Initialize sets up the renderer and picks the image ID used for all frames
+func (r *KittyRenderer) Initialize() error {
+	// Derive the ID from our PID so we don't clobber images from other programs
+	r.imageID = uint32(os.Getpid())%0xFFFFFF + 1
+	r.initialized = true
+	return nil
+}
+
+// Cleanup performs cleanup
+func (r *KittyRenderer) Cleanup() error {
+	r.initialized = false
+	return nil
+}
+
+// Render converts an image to a Kitty graphics protocol escape sequence
+func (r *KittyRenderer) Render(img image.Image, options types.RenderOptions) (string, error) {
+	if !r.initialized {
+		return "", fmt.Errorf("renderer not initialized")
+	}
+
+	cols, rows := options.Width, options.Height
+	if cols == 0 || rows == 0 {
+		return "", fmt.Errorf("invalid dimensions: width=%d, height=%d", cols, rows)
+	}
+
+	// Scale down to the pixel budget for the cell area; the video decoder
+	// usually delivers frames at exactly this size so no resize happens
+	targetWidth, targetHeight := FramePixelSize(options)
+	bounds := img.Bounds()
+	if bounds.Dx() > targetWidth || bounds.Dy() > targetHeight {
+		img = resize.Resize(uint(targetWidth), uint(targetHeight), img, resize.Bilinear)
+		bounds = img.Bounds()
+	}
+
+	img = adjustImage(img, options.Adjustments)
+
+	// The terminal blends real alpha itself, so only flatten onto explicit backgrounds
+	if options.Background == types.BACKGROUND_SOLID || options.Background == types.BACKGROUND_CHECKERBOARD {
+		img = compositeBackground(img, options)
+	}
+
+	// Convert to tightly packed, non-premultiplied RGBA as f=32 expects
+	rgba := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+
+	// Compress the pixel data - raw RGBA is large and compresses very well
+	var compressed bytes.Buffer
+	zw, err := zlib.NewWriterLevel(&compressed, zlib.BestSpeed)
+	if err != nil {
+		return "", fmt.Errorf("failed to create zlib writer: %w", err)
+	}
+	if _, err := zw.Write(rgba.Pix); err != nil {
+		return "", fmt.Errorf("failed to compress frame: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return "", fmt.Errorf("failed to compress frame: %w", err)
+	}
+
+	payload := base64.StdEncoding.EncodeToString(compressed.Bytes())
+
+	// a=T transmits and displays, f=32 is RGBA, o=z is zlib, c/r size the placement in cells,
+	// i/p keep image and placement IDs stable so the old frame is replaced, q=2 silences replies
+	control := fmt.Sprintf("a=T,f=32,o=z,s=%d,v=%d,c=%d,r=%d,i=%d,p=1,q=2",
+		bounds.Dx(), bounds.Dy(), cols, rows, r.imageID)
+
+	return graphicsOutput(options, r.encodeChunks(control, payload)), nil
+}
+
+// encodeChunks splits the base64 payload into APC sequences of at most kittyChunkSize bytes
+// Only the first chunk carries the control keys; m=1 marks that more chunks follow
+func (r *KittyRenderer) encodeChunks(control, payload string) string {
+	var sb strings.Builder
+	sb.Grow(len(payload) + (len(payload)/kittyChunkSize+1)*16 + len(control))
+
+	for offset := 0; offset < len(payload) || offset == 0; offset += kittyChunkSize {
+		end := offset + kittyChunkSize
+		more := 1
+		if end >= len(payload) {
+			end = len(payload)
+			more = 0
+		}
+
+		sb.WriteString("\x1b_G")
+		if offset == 0 {
+			sb.WriteString(control)
+			sb.WriteByte(',')
+		}
+		sb.WriteString(fmt.Sprintf("m=%d;", more))
+		sb.WriteString(payload[offset:end])
+		sb.WriteString("\x1b\\")
+
+		if more == 0 {
+			break
+		}
+	}
+
+	return sb.String()
+}
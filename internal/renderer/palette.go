@@ -0,0 +1,159 @@
+package renderer
+
+import (
+	"math"
+	"sync"
+)
+
+// ansiCubeLevels are the channel intensities of the xterm 6x6x6 color cube
+var ansiCubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// ansi256Palette holds the RGB value of every xterm 256-color index
+var ansi256Palette = newAnsi256Palette()
+
+// newAnsi256Palette builds the xterm 256-color table
+func newAnsi256Palette() [256][3]uint8 {
+	var palette [256][3]uint8
+
+	// 0-15: system colors (xterm defaults; actual values vary by theme)
+	system := [16][3]uint8{
+		{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+		{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+		{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+		{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+	}
+	copy(palette[:16], system[:])
+
+	// 16-231: 6x6x6 color cube
+	for r := 0; r < 6; r++ {
+		for g := 0; g < 6; g++ {
+			for b := 0; b < 6; b++ {
+				palette[16+36*r+6*g+b] = [3]uint8{ansiCubeLevels[r], ansiCubeLevels[g], ansiCubeLevels[b]}
+			}
+		}
+	}
+
+	// 232-255: grayscale ramp
+	for i := 0; i < 24; i++ {
+		level := uint8(8 + 10*i)
+		palette[232+i] = [3]uint8{level, level, level}
+	}
+
+	return palette
+}
+
+// ansiMatcher maps colors to the perceptually nearest entry of an ANSI palette
+// Distances are measured in Oklab; results for every 15-bit color bucket are
+// precomputed on first use so a lookup is a single table read.
+type ansiMatcher struct {
+	palette []oklab
+	lut     []uint8
+	once    sync.Once
+}
+
+var (
+	ansi256Matcher = newAnsiMatcher(ansi256Palette[:])
+	ansi16Matcher  = newAnsiMatcher(ansi256Palette[:16])
+)
+
+// newAnsiMatcher creates a matcher over the given palette (at most 256 colors)
+func newAnsiMatcher(palette [][3]uint8) *ansiMatcher {
+	m := &ansiMatcher{palette: make([]oklab, len(palette))}
+	for i, c := range palette {
+		m.palette[i] = rgbToOklab(c[0], c[1], c[2])
+	}
+	return m
+}
+
+// nearest returns the palette index closest to the given color
+func (m *ansiMatcher) nearest(r, g, b uint8) int {
+	m.once.Do(m.buildLUT)
+	return int(m.lut[histogramKey(r, g, b)])
+}
+
+// buildLUT fills the lookup table, matching each bucket by its center color
+func (m *ansiMatcher) buildLUT() {
+	m.lut = make([]uint8, histogramSize)
+	for key := range m.lut {
+		r := bucketLevel(key >> 10 & 0x1F)
+		g := bucketLevel(key >> 5 & 0x1F)
+		b := bucketLevel(key & 0x1F)
+		m.lut[key] = uint8(m.search(rgbToOklab(r, g, b)))
+	}
+}
+
+// bucketLevel returns the channel value a 5-bit bucket is matched by: its
+// center, except that the end buckets use 0 and 255 so black and white match
+// themselves rather than the nearest dark or light gray
+func bucketLevel(bucket int) uint8 {
+	switch bucket {
+	case 0:
+		return 0
+	case 0x1F:
+		return 255
+	default:
+		return uint8(bucket)<<3 | 4
+	}
+}
+
+// search scans the whole palette for the entry nearest to c
+func (m *ansiMatcher) search(c oklab) int {
+	best, bestDist := 0, math.MaxFloat64
+	for i, p := range m.palette {
+		if dist := c.distance(p); dist < bestDist {
+			best, bestDist = i, dist
+		}
+	}
+	return best
+}
+
+// rgbToAnsi256 converts RGB values to the perceptually closest ANSI 256-color code
+func rgbToAnsi256(red, green, blue uint8) int {
+	return ansi256Matcher.nearest(red, green, blue)
+}
+
+// rgbToAnsi16 converts RGB values to the perceptually closest of the 16 basic ANSI colors
+func rgbToAnsi16(red, green, blue uint8) int {
+	return ansi16Matcher.nearest(red, green, blue)
+}
+
+// oklab is a color in the Oklab perceptual color space (L, a, b)
+type oklab [3]float64
+
+// srgbToLinear maps 8-bit sRGB channel values to linear light
+var srgbToLinear = newSRGBToLinearTable()
+
+// newSRGBToLinearTable builds the sRGB decoding table
+func newSRGBToLinearTable() [256]float64 {
+	var table [256]float64
+	for i := range table {
+		v := float64(i) / 255
+		if v <= 0.04045 {
+			table[i] = v / 12.92
+		} else {
+			table[i] = math.Pow((v+0.055)/1.055, 2.4)
+		}
+	}
+	return table
+}
+
+// rgbToOklab converts an sRGB color to Oklab
+func rgbToOklab(r, g, b uint8) oklab {
+	lr, lg, lb := srgbToLinear[r], srgbToLinear[g], srgbToLinear[b]
+
+	l := math.Cbrt(0.4122214708*lr + 0.5363325363*lg + 0.0514459929*lb)
+	m := math.Cbrt(0.2119034982*lr + 0.6806995451*lg + 0.1073969566*lb)
+	s := math.Cbrt(0.0883024619*lr + 0.2817188376*lg + 0.6299787005*lb)
+
+	return oklab{
+		0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		0.0259040371*l + 0.7827717662*m - 0.8086757660*s,
+	}
+}
+
+// distance returns the squared Euclidean distance between two Oklab colors
+func (c oklab) distance(o oklab) float64 {
+	dl, da, db := c[0]-o[0], c[1]-o[1], c[2]-o[2]
+	return dl*dl + da*da + db*db
+}
@@ -0,0 +1,69 @@
+package renderer
+
+import "testing"
+
+func TestRGBToAnsi(t *testing.T) {
+	tests := []struct {
+		name    string
+		rgb     [3]uint8
+		want256 int
+		want16  int
+	}{
+		{"black", [3]uint8{0, 0, 0}, 0, 0},
+		{"white", [3]uint8{255, 255, 255}, 15, 15},
+		{"red", [3]uint8{255, 0, 0}, 9, 9},
+		{"green", [3]uint8{0, 255, 0}, 10, 10},
+		{"blue", [3]uint8{0, 0, 255}, 21, 4},
+		{"yellow", [3]uint8{255, 255, 0}, 11, 11},
+		{"xterm dark red", [3]uint8{205, 0, 0}, 1, 1},
+		{"cube orange", [3]uint8{215, 95, 0}, 166, 9},
+		{"cube rose", [3]uint8{135, 95, 95}, 95, 8},
+		{"steel blue", [3]uint8{100, 150, 200}, 68, 8},
+		{"mid gray", [3]uint8{128, 128, 128}, 102, 8},
+		{"light gray", [3]uint8{200, 200, 200}, 252, 7},
+		{"very dark gray", [3]uint8{30, 30, 30}, 234, 0},
+		// Near-grays land on the 24-step ramp, not on the coarse cube level
+		// (95, 95, 95) a per-channel cube match would give
+		{"dark gray", [3]uint8{78, 78, 78}, 239, 8},
+		{"greenish dark gray", [3]uint8{60, 64, 60}, 238, 8},
+		{"gray between cube levels", [3]uint8{118, 118, 118}, 243, 8},
+		// Slight tints stay neutral rather than picking up a cube hue
+		{"bluish gray", [3]uint8{128, 128, 138}, 102, 8},
+		{"warm gray", [3]uint8{140, 135, 130}, 102, 8},
+		{"cool gray", [3]uint8{120, 124, 128}, 8, 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, g, b := tt.rgb[0], tt.rgb[1], tt.rgb[2]
+			if got := rgbToAnsi256(r, g, b); got != tt.want256 {
+				t.Errorf("rgbToAnsi256(%v) = %d %v, want %d %v",
+					tt.rgb, got, ansi256Palette[got], tt.want256, ansi256Palette[tt.want256])
+			}
+			if got := rgbToAnsi16(r, g, b); got != tt.want16 {
+				t.Errorf("rgbToAnsi16(%v) = %d %v, want %d %v",
+					tt.rgb, got, ansi256Palette[got], tt.want16, ansi256Palette[tt.want16])
+			}
+		})
+	}
+}
+
+func TestAnsi256Palette(t *testing.T) {
+	tests := []struct {
+		index int
+		want  [3]uint8
+	}{
+		{16, [3]uint8{0, 0, 0}},
+		{21, [3]uint8{0, 0, 255}},
+		{102, [3]uint8{135, 135, 135}},
+		{231, [3]uint8{255, 255, 255}},
+		{232, [3]uint8{8, 8, 8}},
+		{255, [3]uint8{238, 238, 238}},
+	}
+
+	for _, tt := range tests {
+		if got := ansi256Palette[tt.index]; got != tt.want {
+			t.Errorf("ansi256Palette[%d] = %v, want %v", tt.index, got, tt.want)
+		}
+	}
+}
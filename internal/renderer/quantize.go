@@ -0,0 +1,218 @@
+package renderer
+
+import (
+	"image"
+	"sort"
+)
+
+// Histogram resolution: colors are bucketed to 5 bits per channel
+const (
+	histogramBits = 5
+	histogramSize = 1 << (3 * histogramBits)
+)
+
+// histogramKey returns the 15-bit bucket for an 8-bit RGB color
+func histogramKey(r, g, b uint8) int {
+	return int(r>>3)<<10 | int(g>>3)<<5 | int(b>>3)
+}
+
+// colorHistogram counts pixels per 15-bit color bucket and keeps the sum of
+// the exact colors so bucket averages don't lose precision
+type colorHistogram struct {
+	count []int
+	sum   [][3]int
+	keys  []int // buckets that contain at least one pixel
+}
+
+// newColorHistogram builds a histogram over all pixels of the image
+func newColorHistogram(img image.Image) *colorHistogram {
+	h := &colorHistogram{
+		count: make([]int, histogramSize),
+		sum:   make([][3]int, histogramSize),
+	}
+
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			rv, gv, bv, _ := img.At(x, y).RGBA()
+			r8, g8, b8 := uint8(rv>>8), uint8(gv>>8), uint8(bv>>8)
+			key := histogramKey(r8, g8, b8)
+			if h.count[key] == 0 {
+				h.keys = append(h.keys, key)
+			}
+			h.count[key]++
+			h.sum[key][0] += int(r8)
+			h.sum[key][1] += int(g8)
+			h.sum[key][2] += int(b8)
+		}
+	}
+
+	return h
+}
+
+// average returns the mean color of a bucket
+func (h *colorHistogram) average(key int) [3]uint8 {
+	n := h.count[key]
+	return [3]uint8{uint8(h.sum[key][0] / n), uint8(h.sum[key][1] / n), uint8(h.sum[key][2] / n)}
+}
+
+// colorBox is a set of histogram buckets handled as one unit by median cut
+type colorBox struct {
+	keys    []int
+	pixels  int
+	channel int // widest channel
+	extent  int // range of the widest channel
+}
+
+// newColorBox creates a box over the given buckets and measures its extent
+func newColorBox(h *colorHistogram, keys []int) colorBox {
+	box := colorBox{keys: keys}
+	for _, key := range keys {
+		box.pixels += h.count[key]
+	}
+	box.channel, box.extent = box.channelRange(h)
+	return box
+}
+
+// channelRange returns the widest channel of the box and its extent
+func (b *colorBox) channelRange(h *colorHistogram) (int, int) {
+	lo := [3]int{255, 255, 255}
+	hi := [3]int{0, 0, 0}
+	for _, key := range b.keys {
+		c := h.average(key)
+		for ch := 0; ch < 3; ch++ {
+			if int(c[ch]) < lo[ch] {
+				lo[ch] = int(c[ch])
+			}
+			if int(c[ch]) > hi[ch] {
+				hi[ch] = int(c[ch])
+			}
+		}
+	}
+
+	channel := 0
+	for ch := 1; ch < 3; ch++ {
+		if hi[ch]-lo[ch] > hi[channel]-lo[channel] {
+			channel = ch
+		}
+	}
+	return channel, hi[channel] - lo[channel]
+}
+
+// medianCut builds a palette of at most size colors from the histogram
+// Boxes are split along their widest channel at the pixel-weighted median,
+// always choosing the box with the largest range times population next
+func medianCut(h *colorHistogram, size int) [][3]uint8 {
+	if len(h.keys) == 0 {
+		return [][3]uint8{{0, 0, 0}}
+	}
+
+	boxes := []colorBox{newColorBox(h, append([]int(nil), h.keys...))}
+
+	for len(boxes) < size {
+		// Pick the box whose split reduces error the most (approximately)
+		best, bestScore := -1, 0
+		for i := range boxes {
+			if len(boxes[i].keys) < 2 {
+				continue
+			}
+			if score := boxes[i].extent * boxes[i].pixels; score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		if best < 0 {
+			break
+		}
+
+		box := boxes[best]
+		bestChannel := box.channel
+		sort.Slice(box.keys, func(a, b int) bool {
+			return h.average(box.keys[a])[bestChannel] < h.average(box.keys[b])[bestChannel]
+		})
+
+		// Split at the pixel-weighted median
+		half, seen, split := box.pixels/2, 0, 1
+		for i, key := range box.keys[:len(box.keys)-1] {
+			seen += h.count[key]
+			split = i + 1
+			if seen >= half {
+				break
+			}
+		}
+
+		boxes[best] = newColorBox(h, box.keys[:split])
+		boxes = append(boxes, newColorBox(h, box.keys[split:]))
+	}
+
+	palette := make([][3]uint8, len(boxes))
+	for i, box := range boxes {
+		var sum [3]int
+		for _, key := range box.keys {
+			sum[0] += h.sum[key][0]
+			sum[1] += h.sum[key][1]
+			sum[2] += h.sum[key][2]
+		}
+		palette[i] = [3]uint8{uint8(sum[0] / box.pixels), uint8(sum[1] / box.pixels), uint8(sum[2] / box.pixels)}
+	}
+
+	return palette
+}
+
+// paletteMapper finds the nearest palette entry for a color, caching results
+// per 15-bit bucket so repeated lookups are a single table read
+type paletteMapper struct {
+	palette [][3]uint8
+	cache   []int16
+}
+
+// newPaletteMapper creates a mapper for the given palette
+func newPaletteMapper(palette [][3]uint8) *paletteMapper {
+	m := &paletteMapper{
+		palette: palette,
+		cache:   make([]int16, histogramSize),
+	}
+	for i := range m.cache {
+		m.cache[i] = -1
+	}
+	return m
+}
+
+// nearest returns the index of the closest palette color
+func (m *paletteMapper) nearest(r, g, b uint8) int {
+	key := histogramKey(r, g, b)
+	if idx := m.cache[key]; idx >= 0 {
+		return int(idx)
+	}
+
+	// Search from the bucket center so the cached answer suits every color in it
+	cr, cg, cb := int(r&0xF8)+4, int(g&0xF8)+4, int(b&0xF8)+4
+	best, bestDist := 0, 1<<30
+	for i, p := range m.palette {
+		dr, dg, db := cr-int(p[0]), cg-int(p[1]), cb-int(p[2])
+		// Weighted distance approximating perceived brightness differences
+		dist := 2*dr*dr + 4*dg*dg + 3*db*db
+		if dist < bestDist {
+			best, bestDist = i, dist
+		}
+	}
+
+	m.cache[key] = int16(best)
+	return best
+}
+
+// histogramError returns the mean squared error of mapping the histogram onto the palette
+func (m *paletteMapper) histogramError(h *colorHistogram) float64 {
+	var sum float64
+	pixels := 0
+	for _, key := range h.keys {
+		c := h.average(key)
+		p := m.palette[m.nearest(c[0], c[1], c[2])]
+		dr, dg, db := int(c[0])-int(p[0]), int(c[1])-int(p[1]), int(c[2])-int(p[2])
+		sum += float64(h.count[key] * (dr*dr + dg*dg + db*db))
+		pixels += h.count[key]
+	}
+	if pixels == 0 {
+		return 0
+	}
+	return sum / float64(pixels)
+}
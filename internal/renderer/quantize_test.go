@@ -0,0 +1,101 @@
+package renderer
+
+import (
+	"image"
+	"image/color"
+	"terminaltube/pkg/types"
+	"testing"
+)
+
+// paletteImage returns an image cycling through the given colors
+func paletteImage(width, height int, colors ...color.RGBA) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, colors[(y*width+x)%len(colors)])
+		}
+	}
+	return img
+}
+
+func TestPaletteSize(t *testing.T) {
+	img := testImage(64, 64)
+
+	tests := []struct {
+		paletteSize int
+		want        int
+	}{
+		{2, 2},
+		{16, 16},
+		{64, 64},
+		{0, 256}, // Unset means the SIXEL maximum
+		{1000, 256},
+	}
+
+	for _, tt := range tests {
+		r := NewSixelRenderer()
+		r.updatePalette(img, types.RenderOptions{PaletteSize: tt.paletteSize})
+		if got := len(r.mapper.palette); got != tt.want {
+			t.Errorf("PaletteSize %d gave %d colors, want %d", tt.paletteSize, got, tt.want)
+		}
+	}
+}
+
+func TestPaletteFewerColors(t *testing.T) {
+	colors := []color.RGBA{
+		{0, 0, 0, 255},
+		{255, 255, 255, 255},
+		{200, 30, 30, 255},
+		{30, 200, 30, 255},
+		{17, 34, 201, 255},
+	}
+	img := paletteImage(10, 10, colors...)
+
+	r := NewSixelRenderer()
+	r.updatePalette(img, types.RenderOptions{PaletteSize: 16})
+
+	if len(r.mapper.palette) != len(colors) {
+		t.Fatalf("palette has %d colors, want %d", len(r.mapper.palette), len(colors))
+	}
+	for _, c := range colors {
+		got := r.mapper.palette[r.mapper.nearest(c.R, c.G, c.B)]
+		if got != [3]uint8{c.R, c.G, c.B} {
+			t.Errorf("%v maps to %v, want it exactly", c, got)
+		}
+	}
+}
+
+func TestTemporalPalette(t *testing.T) {
+	first := testImage(32, 32)
+	similar := testImage(34, 32)
+	different := paletteImage(32, 32, color.RGBA{0, 255, 0, 255}, color.RGBA{255, 0, 255, 255})
+
+	tests := []struct {
+		name      string
+		temporal  bool
+		next      image.Image
+		wantReuse bool
+	}{
+		{"similar frame keeps the palette", true, similar, true},
+		{"same frame keeps the palette", true, first, true},
+		{"different frame replaces it", true, different, false},
+		{"without TemporalPalette every frame gets its own", false, first, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := types.RenderOptions{PaletteSize: 32, TemporalPalette: tt.temporal}
+			r := NewSixelRenderer()
+			r.updatePalette(first, options)
+			previous, previousPalette := r.mapper, r.palette
+
+			r.updatePalette(tt.next, options)
+			if reused := r.mapper == previous; reused != tt.wantReuse {
+				t.Errorf("palette reused = %v, want %v", reused, tt.wantReuse)
+			}
+			if tt.wantReuse && r.palette != previousPalette {
+				t.Errorf("palette definition changed although the palette was kept")
+			}
+		})
+	}
+}
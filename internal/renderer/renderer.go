@@ -1,10 +1,85 @@
 package renderer
 
 import (
+	"fmt"
 	"image"
+	"terminaltube/internal/terminal"
 	"terminaltube/pkg/types"
 )
 
+// Approximate pixels per character cell used by the pixel-based protocols
+// (Kitty, iTerm2) when the terminal didn't report its cell size. The terminal
+// scales the image to the requested cell area, so this only controls how much
+// detail is sent.
+const (
+	cellPixelWidth  = 8
+	cellPixelHeight = 16
+)
+
+// Pixels per character cell SIXEL frames are drawn at when the terminal didn't
+// report its cell size. SIXEL images are not scaled by the terminal, so cells
+// are assumed a little short to keep frames from running past the bottom of
+// the screen.
+const (
+	sixelCellPixelWidth  = 10
+	sixelCellPixelHeight = 19
+)
+
+// CellSampling returns how many source pixels the renderer for the options'
+// mode samples across and down each character cell
+func CellSampling(options types.RenderOptions) (int, int) {
+	knownCell := options.CellPixelWidth > 0 && options.CellPixelHeight > 0
+
+	switch options.Mode {
+	case types.SIXEL, types.KITTY, types.ITERM2:
+		if knownCell {
+			return options.CellPixelWidth, options.CellPixelHeight
+		}
+		if options.Mode == types.SIXEL {
+			return sixelCellPixelWidth, sixelCellPixelHeight
+		}
+		return cellPixelWidth, cellPixelHeight
+	case types.BLOCKS:
+		return options.BlockGlyphs.CellSize()
+	case types.BRAILLE:
+		return 2, 4
+	case types.ASCII_SHAPE:
+		return shapeCellWidth, shapeCellHeight
+	default:
+		// Half blocks, and ASCII which averages the two
+		return 1, 2
+	}
+}
+
+// FramePixelSize returns the pixel size a frame of options.Width x
+// options.Height cells is sampled at, which is the ideal decode size for video
+func FramePixelSize(options types.RenderOptions) (int, int) {
+	cellWidth, cellHeight := CellSampling(options)
+	width, height := options.Width*cellWidth, options.Height*cellHeight
+
+	// SIXEL draws in bands of 6 pixel rows
+	if options.Mode == types.SIXEL {
+		height = max(6, height/6*6)
+	}
+	return width, height
+}
+
+// originPrefix returns the escape moving the cursor to the options' origin,
+// or nothing when the frame is drawn from the current cursor position
+func originPrefix(options types.RenderOptions) string {
+	if options.OriginRow == 0 && options.OriginColumn == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\033[%d;%dH", options.OriginRow+1, options.OriginColumn+1)
+}
+
+// graphicsOutput places a graphics protocol sequence at the options' origin,
+// wrapped for the multiplexer in between if there is one
+// The cursor movement stays outside the wrapper so the multiplexer tracks it.
+func graphicsOutput(options types.RenderOptions, seq string) string {
+	return originPrefix(options) + terminal.WrapPassthrough(options.Passthrough, seq)
+}
+
 // Renderer defines the interface for different rendering backends
 type Renderer interface {
 	// Render converts an image to a string representation for terminal display
@@ -25,8 +100,9 @@ type Renderer interface {
 
 // RendererManager manages different rendering backends
 type RendererManager struct {
-	renderers    map[types.RenderMode]Renderer
-	capabilities types.TerminalCapabilities
+	renderers     map[types.RenderMode]Renderer
+	capabilities  types.TerminalCapabilities
+	preferredMode *types.RenderMode
 }
 
 // NewRendererManager creates a new renderer manager
@@ -46,17 +122,48 @@ func NewRendererManager(capabilities types.TerminalCapabilities) *RendererManage
 func (rm *RendererManager) registerRenderers() {
 	// Always register ASCII renderers as fallbacks
 	rm.renderers[types.ASCII_GRAY] = NewASCIIRenderer(types.ASCII_GRAY)
-	rm.renderers[types.ASCII_COLOR] = NewASCIIRenderer(types.ASCII_COLOR)
+	rm.renderers[types.ASCII_16] = NewASCIIRenderer(types.ASCII_16)
+	rm.renderers[types.ASCII_SHAPE] = NewASCIIRenderer(types.ASCII_SHAPE)
+
+	// Register 256-color ASCII renderer if supported
+	if rm.capabilities.TrueColor || rm.capabilities.Color256 {
+		rm.renderers[types.ASCII_COLOR] = NewASCIIRenderer(types.ASCII_COLOR)
+	}
 
 	// Register true-color renderer if supported
 	if rm.capabilities.TrueColor {
-		rm.renderers[types.EXACT] = NewUnicodeRenderer()
+		rm.renderers[types.EXACT] = NewUnicodeRenderer(types.EXACT)
+	}
+
+	// Register 256-color half-block renderer if supported
+	if rm.capabilities.TrueColor || rm.capabilities.Color256 {
+		rm.renderers[types.HALFBLOCK_256] = NewUnicodeRenderer(types.HALFBLOCK_256)
+	}
+
+	// Register braille renderer if Unicode is available
+	if rm.capabilities.UnicodeSupport {
+		rm.renderers[types.BRAILLE] = NewBrailleRenderer(rm.capabilities.TrueColor)
+	}
+
+	// Register block glyph renderer if Unicode and color are available
+	if rm.capabilities.UnicodeSupport && (rm.capabilities.TrueColor || rm.capabilities.Color256) {
+		rm.renderers[types.BLOCKS] = NewBlocksRenderer(rm.capabilities.TrueColor)
 	}
 
 	// Register SIXEL renderer if supported
 	if rm.capabilities.SixelSupport {
 		rm.renderers[types.SIXEL] = NewSixelRenderer()
 	}
+
+	// Register Kitty graphics renderer if supported
+	if rm.capabilities.KittyGraphics {
+		rm.renderers[types.KITTY] = NewKittyRenderer()
+	}
+
+	// Register iTerm2 inline image renderer if supported
+	if rm.capabilities.ITerm2Images {
+		rm.renderers[types.ITERM2] = NewITerm2Renderer()
+	}
 }
 
 // GetRenderer returns the appropriate renderer for the given mode
@@ -67,17 +174,36 @@ func (rm *RendererManager) GetRenderer(mode types.RenderMode) (Renderer, bool) {
 
 // GetBestRenderer returns the best available renderer for the terminal
 func (rm *RendererManager) GetBestRenderer() Renderer {
-	// Preference order: SIXEL > EXACT > ASCII_COLOR > ASCII_GRAY
-	modes := []types.RenderMode{types.SIXEL, types.EXACT, types.ASCII_COLOR, types.ASCII_GRAY}
+	return rm.renderers[rm.GetBestMode()]
+}
+
+// SetPreferredMode forces a specific render mode instead of automatic selection
+func (rm *RendererManager) SetPreferredMode(mode types.RenderMode) error {
+	if _, exists := rm.renderers[mode]; !exists {
+		return fmt.Errorf("render mode %s is not available on this terminal", mode)
+	}
+	rm.preferredMode = &mode
+	return nil
+}
+
+// GetBestMode returns the preferred render mode if one was set, otherwise the
+// highest quality mode available for the terminal
+func (rm *RendererManager) GetBestMode() types.RenderMode {
+	if rm.preferredMode != nil {
+		return *rm.preferredMode
+	}
+
+	// Preference order: KITTY > ITERM2 > SIXEL > EXACT > HALFBLOCK_256 > ASCII_COLOR > ASCII_16 > ASCII_GRAY
+	modes := []types.RenderMode{types.KITTY, types.ITERM2, types.SIXEL, types.EXACT, types.HALFBLOCK_256, types.ASCII_COLOR, types.ASCII_16, types.ASCII_GRAY}
 
 	for _, mode := range modes {
-		if renderer, exists := rm.renderers[mode]; exists {
-			return renderer
+		if _, exists := rm.renderers[mode]; exists {
+			return mode
 		}
 	}
 
 	// Fallback to ASCII_GRAY (should always be available)
-	return rm.renderers[types.ASCII_GRAY]
+	return types.ASCII_GRAY
 }
 
 // GetAvailableModes returns all available rendering modes
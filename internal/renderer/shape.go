@@ -0,0 +1,138 @@
+package renderer
+
+// Glyph bitmaps are drawn on a shapeCellWidth x shapeCellHeight grid, which is
+// also how many pixels each character cell is sampled at
+const (
+	shapeCellWidth  = 5
+	shapeCellHeight = 8
+)
+
+// shapeFlatContrast is the brightness range (0-1) below which a cell is
+// treated as flat and drawn from the brightness ramp instead of by shape
+const shapeFlatContrast = 0.25
+
+// shapeGlyph is a character with its (slightly blurred) ink coverage per pixel
+type shapeGlyph struct {
+	char     byte
+	coverage [shapeCellWidth * shapeCellHeight]float64
+}
+
+// shapeGlyphBitmaps are the built-in character shapes, '#' marking ink
+var shapeGlyphBitmaps = map[byte][shapeCellHeight]string{
+	'/':  {"....#", "....#", "...#.", "..#..", "..#..", ".#...", "#....", "#...."},
+	'\\': {"#....", "#....", ".#...", "..#..", "..#..", "...#.", "....#", "....#"},
+	'|':  {"..#..", "..#..", "..#..", "..#..", "..#..", "..#..", "..#..", "..#.."},
+	'_':  {".....", ".....", ".....", ".....", ".....", ".....", ".....", "#####"},
+	'-':  {".....", ".....", ".....", "#####", ".....", ".....", ".....", "....."},
+	'=':  {".....", ".....", "#####", ".....", ".....", "#####", ".....", "....."},
+	'~':  {".....", ".....", ".....", ".##.#", "#..#.", ".....", ".....", "....."},
+	'.':  {".....", ".....", ".....", ".....", ".....", ".....", "..#..", "....."},
+	',':  {".....", ".....", ".....", ".....", ".....", ".....", "..#..", ".#..."},
+	'\'': {"..#..", "..#..", ".....", ".....", ".....", ".....", ".....", "....."},
+	'`':  {".#...", "..#..", ".....", ".....", ".....", ".....", ".....", "....."},
+	'"':  {".#.#.", ".#.#.", ".....", ".....", ".....", ".....", ".....", "....."},
+	':':  {".....", ".....", "..#..", ".....", ".....", "..#..", ".....", "....."},
+	'^':  {"..#..", ".#.#.", "#...#", ".....", ".....", ".....", ".....", "....."},
+	'(':  {"...#.", "..#..", ".#...", ".#...", ".#...", ".#...", "..#..", "...#."},
+	')':  {".#...", "..#..", "...#.", "...#.", "...#.", "...#.", "..#..", ".#..."},
+	'[':  {".###.", ".#...", ".#...", ".#...", ".#...", ".#...", ".#...", ".###."},
+	']':  {".###.", "...#.", "...#.", "...#.", "...#.", "...#.", "...#.", ".###."},
+	'<':  {"....#", "...#.", "..#..", ".#...", "..#..", "...#.", "....#", "....."},
+	'>':  {"#....", ".#...", "..#..", "...#.", "..#..", ".#...", "#....", "....."},
+	'+':  {".....", "..#..", "..#..", "#####", "..#..", "..#..", ".....", "....."},
+	'x':  {".....", ".....", "#...#", ".#.#.", "..#..", ".#.#.", "#...#", "....."},
+	'X':  {"#...#", "#...#", ".#.#.", "..#..", "..#..", ".#.#.", "#...#", "#...#"},
+	'v':  {".....", ".....", "#...#", "#...#", ".#.#.", ".#.#.", "..#..", "....."},
+	'V':  {"#...#", "#...#", "#...#", ".#.#.", ".#.#.", ".#.#.", "..#..", "..#.."},
+	'Y':  {"#...#", "#...#", ".#.#.", "..#..", "..#..", "..#..", "..#..", "..#.."},
+	'T':  {"#####", "..#..", "..#..", "..#..", "..#..", "..#..", "..#..", "..#.."},
+	'L':  {"#....", "#....", "#....", "#....", "#....", "#....", "#....", "#####"},
+	'7':  {"#####", "....#", "...#.", "..#..", ".#...", ".#...", ".#...", ".#..."},
+	'o':  {".....", ".....", ".###.", "#...#", "#...#", "#...#", ".###.", "....."},
+	'O':  {".###.", "#...#", "#...#", "#...#", "#...#", "#...#", "#...#", ".###."},
+	'*':  {".....", "..#..", "#.#.#", ".###.", "#.#.#", "..#..", ".....", "....."},
+	'#':  {".....", ".#.#.", "#####", ".#.#.", ".#.#.", "#####", ".#.#.", "....."},
+	' ':  {".....", ".....", ".....", ".....", ".....", ".....", ".....", "....."},
+}
+
+// shapeGlyphs holds the rasterized glyph set
+var shapeGlyphs = newShapeGlyphs()
+
+// newShapeGlyphs rasterizes the glyph bitmaps, softening each stroke into its
+// neighbours so shapes that are off by a pixel still match well
+func newShapeGlyphs() []shapeGlyph {
+	glyphs := make([]shapeGlyph, 0, len(shapeGlyphBitmaps))
+
+	// Iterate in byte order so ties always resolve the same way
+	for char := 0; char < 128; char++ {
+		bitmap, ok := shapeGlyphBitmaps[byte(char)]
+		if !ok {
+			continue
+		}
+
+		var ink [shapeCellWidth * shapeCellHeight]float64
+		for y, row := range bitmap {
+			for x := 0; x < shapeCellWidth; x++ {
+				if row[x] == '#' {
+					ink[y*shapeCellWidth+x] = 1
+				}
+			}
+		}
+
+		glyph := shapeGlyph{char: byte(char)}
+		for y := 0; y < shapeCellHeight; y++ {
+			for x := 0; x < shapeCellWidth; x++ {
+				var sum float64
+				count := 0
+				for dy := -1; dy <= 1; dy++ {
+					for dx := -1; dx <= 1; dx++ {
+						nx, ny := x+dx, y+dy
+						if nx < 0 || nx >= shapeCellWidth || ny < 0 || ny >= shapeCellHeight {
+							continue
+						}
+						sum += ink[ny*shapeCellWidth+nx]
+						count++
+					}
+				}
+				i := y*shapeCellWidth + x
+				glyph.coverage[i] = 0.6*ink[i] + 0.4*sum/float64(count)
+			}
+		}
+		glyphs = append(glyphs, glyph)
+	}
+
+	return glyphs
+}
+
+// bestShapeGlyph returns the glyph whose shape best matches a cell's
+// brightness values (0-1), or false if the cell is too flat to have a shape
+func bestShapeGlyph(cell []float64) (byte, bool) {
+	lo, hi := cell[0], cell[0]
+	for _, v := range cell {
+		if v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+	if hi-lo < shapeFlatContrast {
+		return 0, false
+	}
+
+	// Compare the contrast-normalized cell so faint and strong edges match alike
+	scale := 1 / (hi - lo)
+	var best byte
+	bestScore := -1.0
+	for _, glyph := range shapeGlyphs {
+		var score float64
+		for i, v := range cell {
+			d := (v-lo)*scale - glyph.coverage[i]
+			score += d * d
+		}
+		if bestScore < 0 || score < bestScore {
+			best, bestScore = glyph.char, score
+		}
+	}
+	return best, true
+}
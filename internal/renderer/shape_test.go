@@ -0,0 +1,58 @@
+package renderer
+
+import "testing"
+
+// shapeCell returns cell brightness values drawn from a bitmap, '#' at ink and
+// everything else at paper
+func shapeCell(bitmap [shapeCellHeight]string, ink, paper float64) []float64 {
+	cell := make([]float64, shapeCellWidth*shapeCellHeight)
+	for y, row := range bitmap {
+		for x := 0; x < shapeCellWidth; x++ {
+			cell[y*shapeCellWidth+x] = paper
+			if row[x] == '#' {
+				cell[y*shapeCellWidth+x] = ink
+			}
+		}
+	}
+	return cell
+}
+
+func TestBestShapeGlyph(t *testing.T) {
+	vertical := [shapeCellHeight]string{"..#..", "..#..", "..#..", "..#..", "..#..", "..#..", "..#..", "..#.."}
+
+	tests := []struct {
+		name   string
+		cell   []float64
+		want   byte
+		wantOK bool
+	}{
+		{"vertical edge", shapeCell(vertical, 1, 0), '|', true},
+		{"faint vertical edge", shapeCell(vertical, 0.65, 0.3), '|', true},
+		{
+			"horizontal edge",
+			shapeCell([shapeCellHeight]string{".....", ".....", ".....", "#####", ".....", ".....", ".....", "....."}, 0.9, 0.1),
+			'-', true,
+		},
+		{
+			"bottom edge",
+			shapeCell([shapeCellHeight]string{".....", ".....", ".....", ".....", ".....", ".....", ".....", "#####"}, 0.9, 0.1),
+			'_', true,
+		},
+		{
+			"rising diagonal",
+			shapeCell([shapeCellHeight]string{"....#", "...#.", "...#.", "..#..", "..#..", ".#...", ".#...", "#...."}, 0.9, 0.1),
+			'/', true,
+		},
+		{"flat cell", shapeCell(vertical, 0.5, 0.5), 0, false},
+		{"contrast below shapeFlatContrast", shapeCell(vertical, 0.6, 0.4), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := bestShapeGlyph(tt.cell)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("bestShapeGlyph() = %q, %v; want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -3,20 +3,27 @@ package renderer
 import (
 	"fmt"
 	"image"
+	"math"
 	"strings"
 	"terminaltube/pkg/types"
 
 	"github.com/nfnt/resize"
 )
 
-// SixelRenderer implements SIXEL graphics rendering with optimized color palette
-// Uses 8-8-4 RGB distribution (256 colors) for better color accuracy
-// Human eyes are more sensitive to red and green, less to blue
+// SixelRenderer implements SIXEL graphics rendering with an adaptive color palette
+// Each image gets a median-cut palette of up to PaletteSize colors. When
+// TemporalPalette is set (video/GIF), the previous palette is reused for as long
+// as it still fits the frame, so colors don't flicker between frames.
 type SixelRenderer struct {
 	initialized bool
-	palette     string // Pre-generated palette string
+	mapper      *paletteMapper // Current palette and its nearest-color cache
+	palette     string         // Palette definition string for the current palette
 }
 
+// paletteReuseTolerance is how much worse (relative) the previous palette may fit
+// a frame than a freshly built one before it is replaced
+const paletteReuseTolerance = 1.3
+
 // NewSixelRenderer creates a new SIXEL renderer
 func NewSixelRenderer() *SixelRenderer {
 	return &SixelRenderer{}
@@ -32,88 +39,59 @@ func (r *SixelRenderer) SupportsMode(mode types.RenderMode) bool {
 	return mode == types.SIXEL
 }
 
-// Initialize sets up the renderer with pre-computed palette
+// Initialize sets up the renderer
 func (r *SixelRenderer) Initialize() error {
-	r.palette = r.generatePalette()
+	r.mapper = nil
+	r.palette = ""
 	r.initialized = true
 	return nil
 }
 
 // Cleanup performs cleanup
 func (r *SixelRenderer) Cleanup() error {
+	r.mapper = nil
+	r.palette = ""
 	r.initialized = false
 	return nil
 }
 
-// generatePalette creates an 8-8-4 RGB palette (256 colors)
-// 8 levels for R, 8 levels for G, 4 levels for B
-// This matches human vision sensitivity (more sensitive to R/G than B)
-func (r *SixelRenderer) generatePalette() string {
-	var sb strings.Builder
+// updatePalette picks the palette for an image, keeping the current one when
+// temporal stability is requested and it is still a good fit
+func (r *SixelRenderer) updatePalette(img image.Image, options types.RenderOptions) {
+	size := options.PaletteSize
+	if size <= 0 || size > 256 {
+		size = 256
+	}
 
-	// 8-8-4 RGB color cube = 256 colors
-	for rLevel := 0; rLevel < 8; rLevel++ {
-		for gLevel := 0; gLevel < 8; gLevel++ {
-			for bLevel := 0; bLevel < 4; bLevel++ {
-				idx := rLevel*32 + gLevel*4 + bLevel
-				// Convert levels to 0-100 percentage with gamma correction
-				rPct := gammaCorrect(rLevel, 7)
-				gPct := gammaCorrect(gLevel, 7)
-				bPct := gammaCorrect(bLevel, 3)
-				sb.WriteString(fmt.Sprintf("#%d;2;%d;%d;%d", idx, rPct, gPct, bPct))
-			}
+	histogram := newColorHistogram(img)
+	fresh := newPaletteMapper(medianCut(histogram, size))
+
+	if options.TemporalPalette && r.mapper != nil && len(r.mapper.palette) <= size {
+		currentError := r.mapper.histogramError(histogram)
+		freshError := fresh.histogramError(histogram)
+		// Small absolute slack so near-perfect fits don't churn on noise
+		if currentError <= freshError*paletteReuseTolerance+4 {
+			return
 		}
 	}
 
-	return sb.String()
+	r.mapper = fresh
+	r.palette = r.generatePalette(fresh.palette)
 }
 
-// gammaCorrect applies gamma correction for better perceptual color distribution
-func gammaCorrect(level, maxLevel int) int {
-	// Use gamma 2.2 for more perceptually uniform distribution
-	normalized := float64(level) / float64(maxLevel)
-	// Apply gamma and convert to percentage
-	corrected := normalized * normalized // Simple gamma approximation
-	return int(corrected * 100)
-}
+// generatePalette creates the SIXEL color register definitions
+// SIXEL specifies RGB components as percentages (0-100)
+func (r *SixelRenderer) generatePalette(palette [][3]uint8) string {
+	var sb strings.Builder
 
-// colorToPalette converts RGB to 8-8-4 palette index with improved accuracy
-func colorToPalette(r, g, b uint8) uint8 {
-	// Apply inverse gamma for better color matching
-	rNorm := float64(r) / 255.0
-	gNorm := float64(g) / 255.0
-	bNorm := float64(b) / 255.0
-
-	// Take square root (inverse of gamma 2.0) for perceptual mapping
-	rLevel := int(sqrt(rNorm) * 7.99)
-	gLevel := int(sqrt(gNorm) * 7.99)
-	bLevel := int(sqrt(bNorm) * 3.99)
-
-	// Clamp to valid ranges
-	if rLevel > 7 {
-		rLevel = 7
+	for idx, c := range palette {
+		rPct := (int(c[0])*100 + 127) / 255
+		gPct := (int(c[1])*100 + 127) / 255
+		bPct := (int(c[2])*100 + 127) / 255
+		sb.WriteString(fmt.Sprintf("#%d;2;%d;%d;%d", idx, rPct, gPct, bPct))
 	}
-	if gLevel > 7 {
-		gLevel = 7
-	}
-	if bLevel > 3 {
-		bLevel = 3
-	}
-
-	return uint8(rLevel*32 + gLevel*4 + bLevel)
-}
 
-// sqrt computes square root for color correction
-func sqrt(x float64) float64 {
-	if x <= 0 {
-		return 0
-	}
-	// Newton-Raphson square root
-	z := x
-	for i := 0; i < 10; i++ {
-		z = (z + x/z) / 2
-	}
-	return z
+	return sb.String()
 }
 
 // Render converts an image to SIXEL representation
@@ -122,56 +100,78 @@ func (r *SixelRenderer) Render(img image.Image, options types.RenderOptions) (st
 		return "", fmt.Errorf("renderer not initialized")
 	}
 
+	if options.Width == 0 || options.Height == 0 {
+		return "", fmt.Errorf("invalid dimensions: width=%d, height=%d", options.Width, options.Height)
+	}
+
+	// SIXEL pixels are drawn 1:1, so scale to exactly fill the cell area;
+	// the video decoder usually delivers frames at this size already
+	targetWidth, targetHeight := FramePixelSize(options)
 	bounds := img.Bounds()
+	if bounds.Dx() != targetWidth || bounds.Dy() != targetHeight {
+		img = resize.Resize(uint(targetWidth), uint(targetHeight), img, resize.Bilinear)
+		bounds = img.Bounds()
+	}
 	width := bounds.Dx()
 	height := bounds.Dy()
 
-	// If image is very large, resize it
-	maxPixels := 1200 * 800
-	if width*height > maxPixels {
-		targetWidth := options.Width * 8
-		targetHeight := options.Height * 16
-		img = resize.Resize(uint(targetWidth), uint(targetHeight), img, resize.Bilinear)
-		bounds = img.Bounds()
-		width = bounds.Dx()
-		height = bounds.Dy()
+	img = adjustImage(img, options.Adjustments)
+
+	// Pixels to leave unset so the terminal shows through
+	var transparent []bool
+	if options.Background == types.BACKGROUND_TRANSPARENT {
+		transparent = transparentMask(img)
 	}
+	img = compositeBackground(img, options)
 
-	// Ensure height is divisible by 6 (required for SIXEL)
-	height6 := ((height + 5) / 6) * 6
+	// Build or reuse the palette for this image
+	r.updatePalette(img, options)
 
 	// Convert image to palette indices
-	pixels := make([]uint8, width*height6)
+	colors := make([][3]uint8, width*height)
 	for y := 0; y < height; y++ {
 		for x := 0; x < width; x++ {
 			c := img.At(bounds.Min.X+x, bounds.Min.Y+y)
 			rv, gv, bv, _ := c.RGBA()
-			pixels[y*width+x] = colorToPalette(uint8(rv>>8), uint8(gv>>8), uint8(bv>>8))
+			colors[y*width+x] = [3]uint8{uint8(rv >> 8), uint8(gv >> 8), uint8(bv >> 8)}
 		}
 	}
-	// Fill remaining rows with black (index 0)
-	for y := height; y < height6; y++ {
-		for x := 0; x < width; x++ {
-			pixels[y*width+x] = 0
-		}
+
+	// Palette entries sit roughly 256/cbrt(N) apart along each channel
+	spread := 255 / math.Cbrt(float64(len(r.mapper.palette)))
+	indices := ditherPixels(colors, width, height, options.Dither, spread, func(c [3]uint8) (int, [3]uint8) {
+		index := r.mapper.nearest(c[0], c[1], c[2])
+		return index, r.mapper.palette[index]
+	})
+
+	pixels := make([]uint8, width*height)
+	for i, index := range indices {
+		pixels[i] = uint8(index)
 	}
 
-	return r.encodeSixel(pixels, width, height6)
+	sixel, err := r.encodeSixel(pixels, transparent, width, height)
+	if err != nil {
+		return "", err
+	}
+	return graphicsOutput(options, sixel), nil
 }
 
 // encodeSixel creates the SIXEL escape sequence
-func (r *SixelRenderer) encodeSixel(pixels []uint8, width, height int) (string, error) {
+// Pixels marked in transparent (which may be nil) are never drawn.
+func (r *SixelRenderer) encodeSixel(pixels []uint8, transparent []bool, width, height int) (string, error) {
 	var sb strings.Builder
 	sb.Grow(width * height / 2) // Rough estimate
 
-	// SIXEL header: ESC P 7;1;q (7=800dpi aspect, 1=transparent background)
+	// SIXEL header: ESC P 7;1;q (7=800dpi aspect, 1=unset pixels keep the background)
 	sb.WriteString("\x1bP7;1;q")
 
 	// Write pre-computed palette
 	sb.WriteString(r.palette)
 
-	// Process in 6-row bands
-	usedColors := make([]bool, 256)
+	// Process in 6-row bands; rows past the image height in the last band
+	// are left unset so the background shows through
+	paletteSize := len(r.mapper.palette)
+	usedColors := make([]bool, paletteSize)
 
 	for y6 := 0; y6 < height; y6 += 6 {
 		// Find colors used in this band
@@ -181,12 +181,14 @@ func (r *SixelRenderer) encodeSixel(pixels []uint8, width, height int) (string,
 		for dy := 0; dy < 6 && y6+dy < height; dy++ {
 			rowOff := (y6 + dy) * width
 			for x := 0; x < width; x++ {
-				usedColors[pixels[rowOff+x]] = true
+				if transparent == nil || !transparent[rowOff+x] {
+					usedColors[pixels[rowOff+x]] = true
+				}
 			}
 		}
 
 		// Process each used color
-		for colorIdx := 0; colorIdx < 256; colorIdx++ {
+		for colorIdx := 0; colorIdx < paletteSize; colorIdx++ {
 			if !usedColors[colorIdx] {
 				continue
 			}
@@ -203,7 +205,8 @@ func (r *SixelRenderer) encodeSixel(pixels []uint8, width, height int) (string,
 				var sixel byte = 0
 				for dy := 0; dy < 6; dy++ {
 					if y6+dy < height {
-						if pixels[(y6+dy)*width+x] == uint8(colorIdx) {
+						i := (y6+dy)*width + x
+						if pixels[i] == uint8(colorIdx) && (transparent == nil || !transparent[i]) {
 							sixel |= 1 << dy
 						}
 					}
@@ -4,44 +4,56 @@ import (
 	"fmt"
 	"image"
 	"image/color"
-	"strings"
 	"terminaltube/pkg/types"
 
 	"github.com/nfnt/resize"
 )
 
-// UnicodeRenderer implements true-color Unicode block rendering
+// UnicodeRenderer implements Unicode half-block rendering
+// EXACT uses 24-bit colors, HALFBLOCK_256 the 256-color palette
 type UnicodeRenderer struct {
+	mode        types.RenderMode
 	initialized bool
+	frames      deltaTracker
 }
 
-// NewUnicodeRenderer creates a new Unicode renderer
-func NewUnicodeRenderer() *UnicodeRenderer {
-	return &UnicodeRenderer{}
+// NewUnicodeRenderer creates a new Unicode renderer for EXACT or HALFBLOCK_256
+func NewUnicodeRenderer(mode types.RenderMode) *UnicodeRenderer {
+	return &UnicodeRenderer{mode: mode}
 }
 
 // Name returns the renderer name
 func (r *UnicodeRenderer) Name() string {
+	if r.mode == types.HALFBLOCK_256 {
+		return "Unicode_256Color"
+	}
 	return "Unicode_TrueColor"
 }
 
 // SupportsMode checks if this renderer supports the given mode
 func (r *UnicodeRenderer) SupportsMode(mode types.RenderMode) bool {
-	return mode == types.EXACT
+	return mode == r.mode
 }
 
 // Initialize sets up the renderer
 func (r *UnicodeRenderer) Initialize() error {
 	r.initialized = true
+	r.frames.reset()
 	return nil
 }
 
 // Cleanup performs cleanup
 func (r *UnicodeRenderer) Cleanup() error {
 	r.initialized = false
+	r.frames.reset()
 	return nil
 }
 
+// ResetFrame makes the next delta frame repaint every cell
+func (r *UnicodeRenderer) ResetFrame() {
+	r.frames.reset()
+}
+
 // Render converts an image to Unicode block representation with true color
 func (r *UnicodeRenderer) Render(img image.Image, options types.RenderOptions) (string, error) {
 	if !r.initialized {
@@ -62,17 +74,26 @@ func (r *UnicodeRenderer) Render(img image.Image, options types.RenderOptions) (
 	// Width stays the same (1 pixel = 1 character width)
 	// Height is doubled because each character is 2 pixels tall
 	resizedImg := resize.Resize(uint(width), uint(pixelHeight), img, resize.Lanczos3)
+	resizedImg = adjustImage(resizedImg, options.Adjustments)
+	resizedImg = compositeBackground(resizedImg, options)
+
+	var frame *textFrame
+	switch r.mode {
+	case types.EXACT:
+		frame = r.renderTrueColorUnicode(resizedImg, width, height, options)
+	case types.HALFBLOCK_256:
+		frame = r.render256ColorUnicode(resizedImg, width, height, options)
+	default:
+		return "", fmt.Errorf("unsupported mode: %s", r.mode.String())
+	}
 
-	return r.renderTrueColorUnicode(resizedImg, width, height)
+	return r.frames.encode(frame, options), nil
 }
 
 // renderTrueColorUnicode renders using Unicode half-blocks with true color
-func (r *UnicodeRenderer) renderTrueColorUnicode(img image.Image, targetWidth, targetHeight int) (string, error) {
+func (r *UnicodeRenderer) renderTrueColorUnicode(img image.Image, targetWidth, targetHeight int, options types.RenderOptions) *textFrame {
 	bounds := img.Bounds()
-	var result strings.Builder
-
-	// Pre-allocate approximate size for efficiency
-	result.Grow(targetWidth * targetHeight * 40)
+	frame := newTextFrame(targetWidth, targetHeight)
 
 	// Process pairs of rows (top and bottom half-blocks)
 	for y := bounds.Min.Y; y < bounds.Max.Y; y += 2 {
@@ -83,7 +104,9 @@ func (r *UnicodeRenderer) renderTrueColorUnicode(img image.Image, targetWidth, t
 			if y+1 < bounds.Max.Y {
 				bottomColor = img.At(x, y+1)
 			} else {
-				bottomColor = color.RGBA{0, 0, 0, 255}
+				// Blend a missing last row into the terminal rather than paint it black
+				bg := options.TerminalBackground
+				bottomColor = color.RGBA{bg[0], bg[1], bg[2], 255}
 			}
 
 			// Convert to RGB
@@ -91,14 +114,44 @@ func (r *UnicodeRenderer) renderTrueColorUnicode(img image.Image, targetWidth, t
 			bottomR, bottomG, bottomB := r.colorToRGB(bottomColor)
 
 			// Use upper half block (▀) with foreground as top color and background as bottom color
-			result.WriteString(fmt.Sprintf("\033[38;2;%d;%d;%dm\033[48;2;%d;%d;%dm▀",
-				topR, topG, topB, bottomR, bottomG, bottomB))
+			frame.set(x-bounds.Min.X, (y-bounds.Min.Y)/2,
+				rgbColor(topR, topG, topB), rgbColor(bottomR, bottomG, bottomB), "▀")
+		}
+	}
+
+	return frame
+}
+
+// render256ColorUnicode renders using Unicode half-blocks with 256-color SGR
+// Both pixels of every cell are mapped (and optionally dithered) to the palette
+func (r *UnicodeRenderer) render256ColorUnicode(img image.Image, targetWidth, targetHeight int, options types.RenderOptions) *textFrame {
+	bounds := img.Bounds()
+	pixelHeight := targetHeight * 2
+	frame := newTextFrame(targetWidth, targetHeight)
+
+	pixels := make([][3]uint8, targetWidth*pixelHeight)
+	for y := 0; y < pixelHeight; y++ {
+		for x := 0; x < targetWidth; x++ {
+			red, green, blue := r.colorToRGB(img.At(bounds.Min.X+x, bounds.Min.Y+y))
+			pixels[y*targetWidth+x] = [3]uint8{red, green, blue}
+		}
+	}
+
+	// The color cube steps are roughly 40-55 apart, as in ASCII color mode
+	colors := ditherPixels(pixels, targetWidth, pixelHeight, options.Dither, 51, func(c [3]uint8) (int, [3]uint8) {
+		index := rgbToAnsi256(c[0], c[1], c[2])
+		return index, ansi256Palette[index]
+	})
+
+	for y := 0; y < targetHeight; y++ {
+		for x := 0; x < targetWidth; x++ {
+			top := colors[(2*y)*targetWidth+x]
+			bottom := colors[(2*y+1)*targetWidth+x]
+			frame.set(x, y, indexedColor(top), indexedColor(bottom), "▀")
 		}
-		// Reset colors and newline
-		result.WriteString("\033[0m\n")
 	}
 
-	return result.String(), nil
+	return frame
 }
 
 // colorToRGB converts a color to RGB values
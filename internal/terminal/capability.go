@@ -10,6 +10,11 @@ import (
 	"golang.org/x/term"
 )
 
+// capabilityQueries are sent to the terminal in one round by DetectCapabilities
+const capabilityQueries = queryCellSize + queryTextAreaSize +
+	querySixelColors + querySixelGeometry + queryKittyGraphics +
+	queryForegroundColor + queryBackgroundColor + querySynchronizedOutput
+
 // DetectCapabilities detects what the current terminal supports
 func DetectCapabilities() (types.TerminalCapabilities, error) {
 	capabilities := types.TerminalCapabilities{}
@@ -23,8 +28,31 @@ func DetectCapabilities() (types.TerminalCapabilities, error) {
 	capabilities.Width = width
 	capabilities.Height = height
 
-	// Detect SIXEL support
-	capabilities.SixelSupport = detectSixelSupport()
+	// Ask the terminal itself; a terminal that doesn't answer leaves replies
+	// empty and detection falls back to environment variables
+	capabilities.Multiplexer = DetectMultiplexer()
+	capabilities.Passthrough = capabilities.Multiplexer
+	if capabilities.Multiplexer == types.MULTIPLEXER_TMUX && !tmuxPassthroughEnabled() {
+		// Wrapped queries would go unanswered and stall detection until the
+		// timeout; unwrapped, tmux answers for itself
+		capabilities.Passthrough = types.MULTIPLEXER_NONE
+	}
+	replies, _ := queryTerminal(capabilityQueries, capabilities.Passthrough, queryTimeout)
+
+	// The outer terminal's text area holds more than this pane, so it only
+	// tells the cell size when there is no multiplexer
+	cols, rows := width, height
+	if capabilities.Multiplexer != types.MULTIPLEXER_NONE {
+		cols, rows = 0, 0
+	}
+	capabilities.CellWidth, capabilities.CellHeight, capabilities.CellSizeMethod = detectCellSize(cols, rows, replies)
+	detectGraphics(&capabilities, replies)
+	if capabilities.Passthrough != capabilities.Multiplexer {
+		// Nothing reaches the outer terminal, whatever the environment says
+		capabilities.ITerm2Images = false
+	}
+	detectColors(&capabilities, replies)
+	capabilities.SynchronizedOutput = detectSynchronizedOutput(capabilities.Multiplexer, replies)
 
 	// Detect color support
 	capabilities.TrueColor = detectTrueColorSupport()
@@ -77,20 +105,76 @@ func GetTerminalSize() (int, int, error) {
 	return 120, 30, nil
 }
 
-// detectSixelSupport checks if the terminal supports SIXEL graphics
+// Graphics queries: XTSMGRAPHICS reads of the SIXEL color register count and
+// maximum image size, and a Kitty graphics query that any implementation
+// answers with an APC reply for image id 31
+const (
+	querySixelColors   = "\033[?1;1;0S"
+	querySixelGeometry = "\033[?2;4;0S"
+	queryKittyGraphics = "\033_Gi=31,s=1,v=1,a=q,t=d,f=24;AAAA\033\\"
+)
+
+// da1SixelAttribute is the device attribute terminals report for SIXEL graphics
+const da1SixelAttribute = 4
+
+// detectGraphics fills in the graphics protocol support from the replies to
+// the capability queries, falling back to environment variables for whatever
+// the terminal didn't answer
+func detectGraphics(capabilities *types.TerminalCapabilities, replies queryReplies) {
+	attributes, answered := replies.csi("?", "c")
+
+	if answered {
+		capabilities.SixelSupport = false
+		for _, attribute := range attributes[min(1, len(attributes)):] {
+			if attribute == da1SixelAttribute {
+				capabilities.SixelSupport = true
+			}
+		}
+		capabilities.SixelMethod = types.DETECTION_QUERY
+	} else {
+		capabilities.SixelSupport = detectSixelSupport()
+		capabilities.SixelMethod = types.DETECTION_ENVIRONMENT
+	}
+
+	// Replies are ?Pi;Ps;Pv... with status 0 on success
+	if params, ok := replies.csi("?1;", "S"); ok && len(params) == 2 && params[0] == 0 {
+		capabilities.SixelColorRegisters = params[1]
+	}
+	if params, ok := replies.csi("?2;", "S"); ok && len(params) == 3 && params[0] == 0 {
+		capabilities.SixelMaxWidth, capabilities.SixelMaxHeight = params[1], params[2]
+	}
+
+	// Any reply, even an error, means the protocol is implemented; terminals
+	// without it answer DA1 alone
+	if _, ok := replies.apc("Gi=31;"); ok || answered {
+		capabilities.KittyGraphics = ok
+		capabilities.KittyMethod = types.DETECTION_QUERY
+	} else {
+		capabilities.KittyGraphics = detectKittyGraphicsSupport()
+		capabilities.KittyMethod = types.DETECTION_ENVIRONMENT
+	}
+
+	// The iTerm2 protocol has no query
+	capabilities.ITerm2Images = detectITerm2ImageSupport()
+	capabilities.ITerm2Method = types.DETECTION_ENVIRONMENT
+}
+
+// detectSixelSupport guesses SIXEL support from the environment, for
+// terminals that don't answer the device attributes query
 func detectSixelSupport() bool {
 	termType := strings.ToLower(os.Getenv("TERM"))
 	termProgram := strings.ToLower(os.Getenv("TERM_PROGRAM"))
 
 	// Known SIXEL-supporting terminals
+	// Generic xterm TERM values are left out: GNOME Terminal, Alacritty and
+	// most other terminals set them without implementing SIXEL.
 	sixelTerminals := []string{
-		"xterm-256color",
-		"xterm-color",
 		"mlterm",
 		"wezterm",
 		"foot",
 		"mintty",
-		"xterm",
+		"yaft",
+		"contour",
 	}
 
 	for _, supportedTerm := range sixelTerminals {
@@ -130,6 +214,59 @@ func detectSixelSupport() bool {
 	return false
 }
 
+// detectKittyGraphicsSupport checks if the terminal implements the Kitty graphics protocol
+func detectKittyGraphicsSupport() bool {
+	termType := strings.ToLower(os.Getenv("TERM"))
+	termProgram := strings.ToLower(os.Getenv("TERM_PROGRAM"))
+
+	// kitty sets TERM=xterm-kitty and exports KITTY_WINDOW_ID to child processes
+	if strings.Contains(termType, "kitty") || os.Getenv("KITTY_WINDOW_ID") != "" {
+		return true
+	}
+
+	// Other terminals implementing the protocol
+	kittyPrograms := []string{
+		"wezterm",
+		"ghostty",
+	}
+
+	for _, program := range kittyPrograms {
+		if strings.Contains(termProgram, program) {
+			return true
+		}
+	}
+
+	if strings.Contains(termType, "ghostty") {
+		return true
+	}
+
+	return false
+}
+
+// detectITerm2ImageSupport checks if the terminal implements the iTerm2 inline image protocol
+func detectITerm2ImageSupport() bool {
+	termProgram := strings.ToLower(os.Getenv("TERM_PROGRAM"))
+
+	// iTerm2 sets TERM_PROGRAM locally and LC_TERMINAL which survives SSH
+	if strings.Contains(termProgram, "iterm.app") || os.Getenv("LC_TERMINAL") == "iTerm2" {
+		return true
+	}
+
+	// Other terminals implementing OSC 1337 File=
+	iterm2Programs := []string{
+		"wezterm",
+		"mintty",
+	}
+
+	for _, program := range iterm2Programs {
+		if strings.Contains(termProgram, program) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // checkWindowsTerminalSixelSupport checks if Windows Terminal version supports SIXEL
 func checkWindowsTerminalSixelSupport() bool {
 	// Try to get Windows Terminal version via PowerShell
@@ -247,10 +384,148 @@ func detectColor256Support() bool {
 		return true
 	}
 
+	// The Linux console and basic terminals only have the 16 ANSI colors
+	basicTerms := []string{"linux", "vt100", "vt220", "ansi", "cons25", "dumb"}
+	for _, term := range basicTerms {
+		if termType == term {
+			return false
+		}
+	}
+
 	// Most modern terminals support at least 256 colors by default
 	return true
 }
 
+// Queries for the default foreground and background colors (OSC 10 and 11),
+// answered with the color as rgb:RRRR/GGGG/BBBB
+const (
+	queryForegroundColor = "\033]10;?\033\\"
+	queryBackgroundColor = "\033]11;?\033\\"
+)
+
+// detectColors fills in the terminal's default colors from the OSC 10/11
+// replies, falling back to COLORFGBG
+func detectColors(capabilities *types.TerminalCapabilities, replies queryReplies) {
+	// A missing reply parses as an invalid color
+	foreground, _ := replies.osc(10)
+	background, _ := replies.osc(11)
+	fg, fgOK := parseXColor(foreground)
+	bg, bgOK := parseXColor(background)
+
+	if fgOK && bgOK {
+		capabilities.ForegroundColor = fg
+		capabilities.BackgroundColor = bg
+		capabilities.LightBackground = isLightColor(bg)
+		capabilities.ColorMethod = types.DETECTION_QUERY
+		return
+	}
+
+	fgIndex, bgIndex, ok := colorFgBg()
+	if !ok {
+		// Assume the common light-on-black default
+		capabilities.ForegroundColor = basicPalette[7]
+		capabilities.BackgroundColor = basicPalette[0]
+		capabilities.ColorMethod = types.DETECTION_NONE
+		return
+	}
+
+	capabilities.ForegroundColor = basicPalette[fgIndex]
+	capabilities.BackgroundColor = basicPalette[bgIndex]
+	// Palette indices 7 (white) and 9-15 (bright colors) are light
+	capabilities.LightBackground = bgIndex == 7 || (bgIndex >= 9 && bgIndex <= 15)
+	capabilities.ColorMethod = types.DETECTION_ENVIRONMENT
+}
+
+// parseXColor parses an X11 color specification as reported by OSC 10/11:
+// rgb:R/G/B (or rgba:R/G/B/A) with 1 to 4 hex digits per component
+func parseXColor(spec string) ([3]uint8, bool) {
+	var color [3]uint8
+
+	value, ok := strings.CutPrefix(spec, "rgb:")
+	if !ok {
+		if value, ok = strings.CutPrefix(spec, "rgba:"); !ok {
+			return color, false
+		}
+	}
+
+	components := strings.Split(value, "/")
+	if len(components) < 3 {
+		return color, false
+	}
+	for i := range color {
+		digits := components[i]
+		if len(digits) == 0 || len(digits) > 4 {
+			return color, false
+		}
+		v, err := strconv.ParseUint(digits, 16, 16)
+		if err != nil {
+			return color, false
+		}
+		// Scale from the component's own range (0xF, 0xFF, 0xFFF or 0xFFFF)
+		maxValue := uint64(1)<<(4*len(digits)) - 1
+		color[i] = uint8((v*255 + maxValue/2) / maxValue)
+	}
+	return color, true
+}
+
+// isLightColor reports whether a background color is light, by its luma
+func isLightColor(c [3]uint8) bool {
+	return 0.299*float64(c[0])+0.587*float64(c[1])+0.114*float64(c[2]) > 127.5
+}
+
+// colorFgBg returns the foreground and background palette indices from
+// COLORFGBG ("fg;bg" or "fg;default;bg", set by rxvt, Konsole and others)
+func colorFgBg() (int, int, bool) {
+	fields := strings.Split(os.Getenv("COLORFGBG"), ";")
+	if len(fields) < 2 {
+		return 0, 0, false
+	}
+
+	fg, err := strconv.Atoi(fields[0])
+	if err != nil || fg < 0 || fg > 15 {
+		// "default" foregrounds are common; guess from the background below
+		fg = -1
+	}
+	bg, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil || bg < 0 || bg > 15 {
+		return 0, 0, false
+	}
+
+	if fg < 0 {
+		fg = 7
+		if bg == 7 || bg >= 9 {
+			fg = 0
+		}
+	}
+	return fg, bg, true
+}
+
+// basicPalette is the xterm default for the 16 basic ANSI colors
+var basicPalette = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// querySynchronizedOutput asks for the state of synchronized output mode
+// (DECRQM for private mode 2026, answered with CSI ? 2026;Ps $ y)
+const querySynchronizedOutput = "\033[?2026$p"
+
+// detectSynchronizedOutput checks the DECRQM reply for mode 2026 support
+// Inside a multiplexer the reply comes from the outer terminal while the mode
+// would be set on the multiplexer, so it is left off there.
+func detectSynchronizedOutput(multiplexer types.Multiplexer, replies queryReplies) bool {
+	if multiplexer != types.MULTIPLEXER_NONE {
+		return false
+	}
+
+	// Ps 1 and 2 are set and reset, 3 permanently set; 0 (unknown) and
+	// 4 (permanently reset) mean the mode can't be used
+	params, ok := replies.csi("?2026;", "$y")
+	return ok && len(params) == 1 && params[0] >= 1 && params[0] <= 3
+}
+
 // detectUnicodeSupport checks if the terminal supports Unicode
 func detectUnicodeSupport() bool {
 	// Check locale settings
@@ -0,0 +1,136 @@
+package terminal
+
+import (
+	"terminaltube/pkg/types"
+	"testing"
+)
+
+func TestParseXColor(t *testing.T) {
+	tests := []struct {
+		spec   string
+		want   [3]uint8
+		wantOK bool
+	}{
+		{"rgb:ffff/ffff/ffff", [3]uint8{255, 255, 255}, true},
+		{"rgb:0000/0000/0000", [3]uint8{0, 0, 0}, true},
+		{"rgb:1e1e/1e1e/2e2e", [3]uint8{30, 30, 46}, true},
+		{"rgb:ff/80/00", [3]uint8{255, 128, 0}, true},
+		{"rgb:f/8/0", [3]uint8{255, 136, 0}, true},
+		{"rgb:fff/000/800", [3]uint8{255, 0, 128}, true},
+		{"rgba:ffff/0000/0000/ffff", [3]uint8{255, 0, 0}, true},
+		{"RGB:ff/ff/ff", [3]uint8{}, false},
+		{"#ffffff", [3]uint8{}, false},
+		{"rgb:ff/ff", [3]uint8{}, false},
+		{"rgb:ff//ff", [3]uint8{}, false},
+		{"rgb:fffff/0/0", [3]uint8{}, false},
+		{"rgb:gg/00/00", [3]uint8{}, false},
+		{"", [3]uint8{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseXColor(tt.spec)
+		if ok != tt.wantOK || (ok && got != tt.want) {
+			t.Errorf("parseXColor(%q) = %v, %v; want %v, %v", tt.spec, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestDetectColorsFromReplies(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantFg    [3]uint8
+		wantBg    [3]uint8
+		wantLight bool
+	}{
+		{
+			"light theme",
+			"\033]10;rgb:0000/0000/0000\033\\\033]11;rgb:ffff/ffff/ffff\033\\",
+			[3]uint8{0, 0, 0}, [3]uint8{255, 255, 255}, true,
+		},
+		{
+			"dark theme, BEL terminated, 2-digit components",
+			"\033]10;rgb:cd/d6/f4\007\033]11;rgb:1e/1e/2e\007",
+			[3]uint8{205, 214, 244}, [3]uint8{30, 30, 46}, false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var capabilities types.TerminalCapabilities
+			detectColors(&capabilities, parseReplies([]byte(tt.input)))
+
+			if capabilities.ColorMethod != types.DETECTION_QUERY {
+				t.Fatalf("ColorMethod = %s, want QUERY", capabilities.ColorMethod)
+			}
+			if capabilities.ForegroundColor != tt.wantFg || capabilities.BackgroundColor != tt.wantBg {
+				t.Errorf("colors = %v on %v, want %v on %v",
+					capabilities.ForegroundColor, capabilities.BackgroundColor, tt.wantFg, tt.wantBg)
+			}
+			if capabilities.LightBackground != tt.wantLight {
+				t.Errorf("LightBackground = %v, want %v", capabilities.LightBackground, tt.wantLight)
+			}
+		})
+	}
+}
+
+func TestDetectColorsFallback(t *testing.T) {
+	tests := []struct {
+		name       string
+		colorFgBg  string
+		wantMethod types.DetectionMethod
+		wantLight  bool
+	}{
+		{"COLORFGBG light", "0;15", types.DETECTION_ENVIRONMENT, true},
+		{"COLORFGBG with default field", "15;default;0", types.DETECTION_ENVIRONMENT, false},
+		{"nothing to go on", "", types.DETECTION_NONE, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("COLORFGBG", tt.colorFgBg)
+
+			// Only the foreground answered, which isn't enough
+			var capabilities types.TerminalCapabilities
+			detectColors(&capabilities, parseReplies([]byte("\033]10;rgb:ff/ff/ff\033\\")))
+
+			if capabilities.ColorMethod != tt.wantMethod || capabilities.LightBackground != tt.wantLight {
+				t.Errorf("got %s, light %v; want %s, light %v",
+					capabilities.ColorMethod, capabilities.LightBackground, tt.wantMethod, tt.wantLight)
+			}
+		})
+	}
+}
+
+func TestDetectSynchronizedOutput(t *testing.T) {
+	tests := []struct {
+		name        string
+		multiplexer types.Multiplexer
+		input       string
+		want        bool
+	}{
+		{"set", types.MULTIPLEXER_NONE, "\033[?2026;1$y", true},
+		{"reset", types.MULTIPLEXER_NONE, "\033[?2026;2$y", true},
+		{"permanently set", types.MULTIPLEXER_NONE, "\033[?2026;3$y", true},
+		{"not recognized", types.MULTIPLEXER_NONE, "\033[?2026;0$y", false},
+		{"permanently reset", types.MULTIPLEXER_NONE, "\033[?2026;4$y", false},
+		{"among other replies", types.MULTIPLEXER_NONE, "\033[?62;4c\033[?2026;2$y\033]11;rgb:0/0/0\033\\", true},
+		{"no reply", types.MULTIPLEXER_NONE, "\033[?62;4c", false},
+		{"other mode", types.MULTIPLEXER_NONE, "\033[?2027;2$y", false},
+		{"ANSI mode reply", types.MULTIPLEXER_NONE, "\033[2026;2$y", false},
+		{"missing value", types.MULTIPLEXER_NONE, "\033[?2026;$y", false},
+		{"extra parameter", types.MULTIPLEXER_NONE, "\033[?2026;2;1$y", false},
+		{"missing intermediate", types.MULTIPLEXER_NONE, "\033[?2026;2y", false},
+		{"truncated", types.MULTIPLEXER_NONE, "\033[?2026;2$", false},
+		{"inside tmux", types.MULTIPLEXER_TMUX, "\033[?2026;2$y", false},
+		{"inside screen", types.MULTIPLEXER_SCREEN, "\033[?2026;1$y", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectSynchronizedOutput(tt.multiplexer, parseReplies([]byte(tt.input))); got != tt.want {
+				t.Errorf("detectSynchronizedOutput(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
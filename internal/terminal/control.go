@@ -1,42 +1,108 @@
 package terminal
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"os"
+	"sync"
 )
 
 // Control provides terminal control functions using ANSI escape sequences
+// All output is buffered. Between BeginFrame and EndFrame it is held back and
+// written in one go by EndFrame; anything written outside a frame is flushed
+// right away, so it stays in order with other output to the terminal.
 type Control struct {
+	// mu guards everything below, as Reset may be called from a signal handler
+	mu      sync.Mutex
+	out     io.Writer
+	buffer  bytes.Buffer
+	inFrame bool
+
 	cursorHidden bool
+	keys         *KeyReader
+	synchronized bool // Wrap frames in synchronized updates (mode 2026)
 }
 
-// NewControl creates a new terminal control instance
+// NewControl creates a new terminal control instance writing to stdout
 func NewControl() *Control {
-	return &Control{}
+	return NewControlWriter(os.Stdout)
+}
+
+// NewControlWriter creates a terminal control instance writing to w, which
+// may be a file, a socket or a bytes.Buffer as well as a terminal
+func NewControlWriter(w io.Writer) *Control {
+	return &Control{out: w}
+}
+
+// Write buffers p for the terminal, implementing io.Writer so frames and
+// text can be printed with fmt.Fprint
+func (c *Control) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.writeLocked(p)
+}
+
+// writeLocked buffers p, flushing unless a frame is in progress; c.mu must be
+// held
+func (c *Control) writeLocked(p []byte) (int, error) {
+	n, _ := c.buffer.Write(p)
+	if c.inFrame {
+		return n, nil
+	}
+	return n, c.flushLocked()
+}
+
+// WriteString buffers s for the terminal
+func (c *Control) WriteString(s string) (int, error) {
+	return c.Write([]byte(s))
+}
+
+// emit writes an escape sequence
+func (c *Control) emit(seq string) error {
+	_, err := c.WriteString(seq)
+	return err
+}
+
+// flushLocked writes out the buffer in a single write; c.mu must be held
+func (c *Control) flushLocked() error {
+	if c.buffer.Len() == 0 {
+		return nil
+	}
+	_, err := c.out.Write(c.buffer.Bytes())
+	c.buffer.Reset()
+	return err
 }
 
 // ClearScreen clears the entire terminal screen and moves cursor to home
 func (c *Control) ClearScreen() error {
-	_, err := fmt.Print("\033[2J\033[H")
-	return err
+	return c.emit("\033[2J\033[H")
+}
+
+// FillScreen clears the screen to the background selected by the SGR
+// parameters sgr (such as "48;5;236") and moves the cursor to the top-left
+// corner
+func (c *Control) FillScreen(sgr string) error {
+	return c.emit("\033[" + sgr + "m\033[2J\033[0m\033[H")
 }
 
 // MoveCursorHome moves the cursor to the top-left corner (1,1)
 func (c *Control) MoveCursorHome() error {
-	_, err := fmt.Print("\033[H")
-	return err
+	return c.emit("\033[H")
 }
 
 // MoveCursor moves the cursor to the specified position (1-based)
 func (c *Control) MoveCursor(row, col int) error {
-	_, err := fmt.Printf("\033[%d;%dH", row, col)
-	return err
+	return c.emit(fmt.Sprintf("\033[%d;%dH", row, col))
 }
 
 // HideCursor hides the terminal cursor
 func (c *Control) HideCursor() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if !c.cursorHidden {
-		_, err := fmt.Print("\033[?25l")
+		_, err := c.writeLocked([]byte("\033[?25l"))
 		if err == nil {
 			c.cursorHidden = true
 		}
@@ -47,8 +113,11 @@ func (c *Control) HideCursor() error {
 
 // ShowCursor shows the terminal cursor
 func (c *Control) ShowCursor() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if c.cursorHidden {
-		_, err := fmt.Print("\033[?25h")
+		_, err := c.writeLocked([]byte("\033[?25h"))
 		if err == nil {
 			c.cursorHidden = false
 		}
@@ -59,45 +128,121 @@ func (c *Control) ShowCursor() error {
 
 // SaveCursorPosition saves the current cursor position
 func (c *Control) SaveCursorPosition() error {
-	_, err := fmt.Print("\033[s")
-	return err
+	return c.emit("\033[s")
 }
 
 // RestoreCursorPosition restores the previously saved cursor position
 func (c *Control) RestoreCursorPosition() error {
-	_, err := fmt.Print("\033[u")
-	return err
+	return c.emit("\033[u")
 }
 
 // SetTitle sets the terminal window title
 func (c *Control) SetTitle(title string) error {
-	_, err := fmt.Printf("\033]0;%s\007", title)
-	return err
+	return c.emit(fmt.Sprintf("\033]0;%s\007", title))
 }
 
 // EnableAlternateScreen switches to the alternate screen buffer
 func (c *Control) EnableAlternateScreen() error {
-	_, err := fmt.Print("\033[?1049h")
-	return err
+	return c.emit("\033[?1049h")
 }
 
 // DisableAlternateScreen switches back to the main screen buffer
 func (c *Control) DisableAlternateScreen() error {
-	_, err := fmt.Print("\033[?1049l")
-	return err
+	return c.emit("\033[?1049l")
 }
 
 // Reset resets all terminal attributes and clears the screen
 func (c *Control) Reset() error {
-	// Reset attributes, show cursor, clear screen
-	_, err := fmt.Print("\033[0m\033[?25h\033[2J\033[H")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.disableKeyInputLocked()
+
+	// Drop out of any frame in progress so the reset goes out at once
+	c.inFrame = false
+
+	// End any synchronized update, reset attributes, show cursor, clear screen
+	reset := "\033[0m\033[?25h\033[2J\033[H"
+	if c.synchronized {
+		reset = "\033[?2026l" + reset
+	}
+	_, err := c.writeLocked([]byte(reset))
 	c.cursorHidden = false
 	return err
 }
 
-// Flush flushes the output buffer
+// EnableKeyInput starts delivering key presses without waiting for Enter
+// Calling it again while enabled returns the same channel.
+func (c *Control) EnableKeyInput() (<-chan KeyEvent, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.keys == nil {
+		keys, err := NewKeyReader()
+		if err != nil {
+			return nil, err
+		}
+		c.keys = keys
+	}
+	return c.keys.Events(), nil
+}
+
+// DisableKeyInput stops key input and restores the terminal's input mode
+func (c *Control) DisableKeyInput() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.disableKeyInputLocked()
+}
+
+// disableKeyInputLocked stops key input if it is on; c.mu must be held
+func (c *Control) disableKeyInputLocked() error {
+	if c.keys == nil {
+		return nil
+	}
+	err := c.keys.Close()
+	c.keys = nil
+	return err
+}
+
+// SetSynchronizedOutput enables wrapping frames in synchronized updates, for
+// terminals that support mode 2026
+func (c *Control) SetSynchronizedOutput(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.synchronized = enabled
+}
+
+// BeginFrame starts a frame: output is held back until EndFrame, and with
+// synchronized output the terminal keeps showing the previous frame until then
+func (c *Control) BeginFrame() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.inFrame = true
+	if c.synchronized {
+		c.buffer.WriteString("\033[?2026h")
+	}
+	return nil
+}
+
+// EndFrame finishes a frame started with BeginFrame, writing it out with a
+// single flush so the terminal can paint it in one go
+func (c *Control) EndFrame() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.synchronized {
+		c.buffer.WriteString("\033[?2026l")
+	}
+	c.inFrame = false
+	return c.flushLocked()
+}
+
+// Flush writes out any buffered output, even in the middle of a frame
 func (c *Control) Flush() error {
-	return nil // fmt.Print automatically flushes
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.flushLocked()
 }
 
 // GetTerminalInfo returns basic terminal information
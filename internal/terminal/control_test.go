@@ -0,0 +1,90 @@
+package terminal
+
+import (
+	"fmt"
+	"testing"
+)
+
+// countingWriter records each Write separately
+type countingWriter struct {
+	writes []string
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes = append(w.writes, string(p))
+	return len(p), nil
+}
+
+func TestFrameIsWrittenOnce(t *testing.T) {
+	tests := []struct {
+		name         string
+		synchronized bool
+		want         string
+	}{
+		{"plain", false, "\033[Hframe 1\033[2;3Hstatus"},
+		{"synchronized", true, "\033[?2026h\033[Hframe 1\033[2;3Hstatus\033[?2026l"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &countingWriter{}
+			c := NewControlWriter(w)
+			c.SetSynchronizedOutput(tt.synchronized)
+
+			c.BeginFrame()
+			c.MoveCursorHome()
+			fmt.Fprintf(c, "frame %d", 1)
+			c.MoveCursor(2, 3)
+			c.WriteString("status")
+			if len(w.writes) != 0 {
+				t.Fatalf("wrote %q before EndFrame", w.writes)
+			}
+			c.EndFrame()
+
+			if len(w.writes) != 1 {
+				t.Fatalf("got %d writes %q, want 1", len(w.writes), w.writes)
+			}
+			if w.writes[0] != tt.want {
+				t.Errorf("got %q, want %q", w.writes[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestOutputOutsideFrameIsFlushed(t *testing.T) {
+	w := &countingWriter{}
+	c := NewControlWriter(w)
+	c.SetSynchronizedOutput(true)
+
+	c.ClearScreen()
+	if len(w.writes) != 1 || w.writes[0] != "\033[2J\033[H" {
+		t.Fatalf("after ClearScreen got %q", w.writes)
+	}
+
+	fmt.Fprintln(c, "menu text")
+	if len(w.writes) != 2 || w.writes[1] != "menu text\n" {
+		t.Fatalf("after Fprintln got %q", w.writes)
+	}
+
+	// Empty frames write nothing but the synchronized update markers
+	c.BeginFrame()
+	c.EndFrame()
+	if len(w.writes) != 3 || w.writes[2] != "\033[?2026h\033[?2026l" {
+		t.Fatalf("after empty frame got %q", w.writes)
+	}
+}
+
+func TestFlushInsideFrame(t *testing.T) {
+	w := &countingWriter{}
+	c := NewControlWriter(w)
+
+	c.BeginFrame()
+	c.WriteString("part 1")
+	c.Flush()
+	c.WriteString("part 2")
+	c.EndFrame()
+
+	if len(w.writes) != 2 || w.writes[0] != "part 1" || w.writes[1] != "part 2" {
+		t.Errorf("got %q, want [part 1 part 2]", w.writes)
+	}
+}
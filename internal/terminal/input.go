@@ -0,0 +1,224 @@
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"time"
+	"unicode/utf8"
+
+	"github.com/muesli/cancelreader"
+	"golang.org/x/term"
+)
+
+// Key identifies a key press read from the terminal
+type Key int
+
+const (
+	// KeyRune is a printable character, stored in KeyEvent.Rune
+	KeyRune Key = iota
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyEnter
+	KeyEscape
+	KeyBackspace
+	KeyCtrlC
+)
+
+// escapeTimeout is how long an escape sequence split across reads may take to
+// complete before its ESC counts as the Escape key; arrow keys over a slow
+// SSH link can arrive in pieces
+const escapeTimeout = 100 * time.Millisecond
+
+// KeyEvent is a single key press
+type KeyEvent struct {
+	Key  Key
+	Rune rune
+}
+
+// KeyReader delivers key presses from stdin while media is playing
+// The terminal is switched to unbuffered, no-echo input for as long as the
+// reader is open; Close restores it and stops the reader goroutine.
+type KeyReader struct {
+	reader  cancelreader.CancelReader
+	restore func() error
+	events  chan KeyEvent
+	done    chan struct{}
+}
+
+// NewKeyReader starts reading key presses from stdin
+func NewKeyReader() (*KeyReader, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return nil, fmt.Errorf("stdin is not a terminal")
+	}
+
+	restore, err := enableKeyInput(fd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enable key input: %w", err)
+	}
+
+	reader, err := cancelreader.NewReader(os.Stdin)
+	if err != nil {
+		restore()
+		return nil, fmt.Errorf("failed to create stdin reader: %w", err)
+	}
+
+	k := &KeyReader{
+		reader:  reader,
+		restore: restore,
+		events:  make(chan KeyEvent, 16),
+		done:    make(chan struct{}),
+	}
+	go k.readLoop()
+
+	return k, nil
+}
+
+// Events returns the channel key presses are delivered on
+func (k *KeyReader) Events() <-chan KeyEvent {
+	return k.events
+}
+
+// Close stops reading and restores the previous terminal input mode
+func (k *KeyReader) Close() error {
+	k.reader.Cancel()
+	<-k.done
+	k.reader.Close()
+	return k.restore()
+}
+
+// readLoop reads stdin until canceled, decoding key presses
+// Input ending in an incomplete sequence is held back until the rest arrives,
+// or decoded as it stands once escapeTimeout passes without more.
+func (k *KeyReader) readLoop() {
+	defer close(k.done)
+
+	chunks := make(chan []byte)
+	go func() {
+		defer close(chunks)
+		buf := make([]byte, 64)
+		for {
+			n, err := k.reader.Read(buf)
+			if err != nil {
+				// Canceled by Close, or stdin went away
+				return
+			}
+			chunks <- append([]byte(nil), buf[:n]...)
+		}
+	}()
+
+	var pending []byte
+	var timeout <-chan time.Time
+	for {
+		final := false
+		select {
+		case chunk, ok := <-chunks:
+			if !ok {
+				return
+			}
+			pending = append(pending, chunk...)
+		case <-timeout:
+			final = true
+		}
+
+		var events []KeyEvent
+		events, pending = parseKeys(pending, final)
+		timeout = nil
+		if len(pending) > 0 {
+			timeout = time.After(escapeTimeout)
+		}
+
+		for _, event := range events {
+			// Drop keys rather than block if nobody is listening
+			select {
+			case k.events <- event:
+			default:
+			}
+		}
+	}
+}
+
+// parseKeys decodes input into key presses, returning an incomplete escape
+// sequence or character at the end separately for more input to complete
+// With final set, everything is decoded as it stands, so a lone ESC is the
+// Escape key.
+func parseKeys(data []byte, final bool) ([]KeyEvent, []byte) {
+	var events []KeyEvent
+
+	for len(data) > 0 {
+		switch b := data[0]; {
+		case b == 0x1b:
+			if !final && escapeIncomplete(data) {
+				return events, data
+			}
+			if len(data) == 1 {
+				events = append(events, KeyEvent{Key: KeyEscape})
+				data = data[1:]
+				continue
+			}
+			if (data[1] == '[' || data[1] == 'O') && len(data) >= 3 {
+				// The final byte names the key; parameters (modifiers) are skipped
+				end := 2
+				for end < len(data) && (data[end] < 0x40 || data[end] > 0x7e) {
+					end++
+				}
+				if end < len(data) {
+					switch data[end] {
+					case 'A':
+						events = append(events, KeyEvent{Key: KeyUp})
+					case 'B':
+						events = append(events, KeyEvent{Key: KeyDown})
+					case 'C':
+						events = append(events, KeyEvent{Key: KeyRight})
+					case 'D':
+						events = append(events, KeyEvent{Key: KeyLeft})
+					}
+				}
+				data = data[min(end+1, len(data)):]
+				continue
+			}
+			events = append(events, KeyEvent{Key: KeyEscape})
+			data = data[1:]
+		case b == 0x03:
+			events = append(events, KeyEvent{Key: KeyCtrlC})
+			data = data[1:]
+		case b == '\r' || b == '\n':
+			events = append(events, KeyEvent{Key: KeyEnter})
+			data = data[1:]
+		case b == 0x7f || b == 0x08:
+			events = append(events, KeyEvent{Key: KeyBackspace})
+			data = data[1:]
+		case b < 0x20:
+			// Other control characters are ignored
+			data = data[1:]
+		default:
+			if !final && !utf8.FullRune(data) {
+				return events, data
+			}
+			r, size := utf8.DecodeRune(data)
+			events = append(events, KeyEvent{Key: KeyRune, Rune: r})
+			data = data[size:]
+		}
+	}
+
+	return events, nil
+}
+
+// escapeIncomplete reports whether data, starting with ESC, ends before its
+// escape sequence does
+func escapeIncomplete(data []byte) bool {
+	if len(data) == 1 {
+		return true
+	}
+	if data[1] != '[' && data[1] != 'O' {
+		return false
+	}
+	for _, b := range data[2:] {
+		if b >= 0x40 && b <= 0x7e {
+			return false
+		}
+	}
+	return true
+}
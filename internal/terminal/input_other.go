@@ -0,0 +1,18 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package terminal
+
+import "golang.org/x/term"
+
+// enableKeyInput puts the console into raw input mode
+// On Windows this only affects input; Ctrl+C arrives as a key press.
+func enableKeyInput(fd int) (func() error, error) {
+	saved, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		return term.Restore(fd, saved)
+	}, nil
+}
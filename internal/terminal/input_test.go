@@ -0,0 +1,49 @@
+package terminal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		final    bool
+		want     []KeyEvent
+		wantRest string
+	}{
+		{"runes", "q ", false, []KeyEvent{{Key: KeyRune, Rune: 'q'}, {Key: KeyRune, Rune: ' '}}, ""},
+		{"arrows", "\x1b[C\x1bOD", false, []KeyEvent{{Key: KeyRight}, {Key: KeyLeft}}, ""},
+		{"modified arrow", "\x1b[1;5A", false, []KeyEvent{{Key: KeyUp}}, ""},
+		{"ctrl+c and enter", "\x03\r", false, []KeyEvent{{Key: KeyCtrlC}, {Key: KeyEnter}}, ""},
+		{"lone escape waits", "\x1b", false, nil, "\x1b"},
+		{"lone escape final", "\x1b", true, []KeyEvent{{Key: KeyEscape}}, ""},
+		{"split csi waits", "x\x1b[", false, []KeyEvent{{Key: KeyRune, Rune: 'x'}}, "\x1b["},
+		{"split parameters wait", "\x1b[1;5", false, nil, "\x1b[1;5"},
+		{"escape then key", "\x1bq", false, []KeyEvent{{Key: KeyEscape}, {Key: KeyRune, Rune: 'q'}}, ""},
+		{"split utf-8 waits", "\xc3", false, nil, "\xc3"},
+		{"utf-8", "\xc3\xa9", false, []KeyEvent{{Key: KeyRune, Rune: 'é'}}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, rest := parseKeys([]byte(tt.input), tt.final)
+			if !reflect.DeepEqual(got, tt.want) || string(rest) != tt.wantRest {
+				t.Errorf("parseKeys(%q, %v) = %v, %q; want %v, %q", tt.input, tt.final, got, rest, tt.want, tt.wantRest)
+			}
+		})
+	}
+}
+
+func TestParseKeysAcrossReads(t *testing.T) {
+	// An arrow key arriving in two reads is one key press, not Escape
+	events, pending := parseKeys([]byte("\x1b"), false)
+	if len(events) != 0 {
+		t.Fatalf("first read gave %v", events)
+	}
+	events, pending = parseKeys(append(pending, "[C"...), false)
+	if !reflect.DeepEqual(events, []KeyEvent{{Key: KeyRight}}) || len(pending) != 0 {
+		t.Errorf("second read gave %v, pending %q", events, pending)
+	}
+}
@@ -0,0 +1,28 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package terminal
+
+import "golang.org/x/sys/unix"
+
+// enableKeyInput turns off line buffering, echo and signal keys on the terminal
+// Ctrl+C arrives as a key press, so playback can stop and restore the terminal
+// itself. Unlike full raw mode, output processing stays enabled, so frames keep
+// their newlines.
+func enableKeyInput(fd int) (func() error, error) {
+	saved, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := *saved
+	mode.Lflag &^= unix.ICANON | unix.ECHO | unix.ISIG
+	mode.Cc[unix.VMIN] = 1
+	mode.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, ioctlSetTermios, &mode); err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		return unix.IoctlSetTermios(fd, ioctlSetTermios, saved)
+	}, nil
+}
@@ -0,0 +1,91 @@
+package terminal
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"terminaltube/pkg/types"
+)
+
+// Passthrough chunk sizes. tmux accepts long strings but large frames are
+// still split so no single sequence hits its input buffer limit; screen drops
+// DCS strings longer than 768 bytes.
+const (
+	tmuxChunkSize   = 4096
+	screenChunkSize = 760
+)
+
+// DetectMultiplexer reports whether we are running inside tmux or GNU screen
+func DetectMultiplexer() types.Multiplexer {
+	switch {
+	case os.Getenv("TMUX") != "":
+		return types.MULTIPLEXER_TMUX
+	case os.Getenv("STY") != "":
+		return types.MULTIPLEXER_SCREEN
+	default:
+		return types.MULTIPLEXER_NONE
+	}
+}
+
+// tmuxPassthroughEnabled asks tmux whether allow-passthrough is on
+// tmux before 3.3 has no such option and always forwards passthrough, so a
+// failure to read it counts as enabled.
+func tmuxPassthroughEnabled() bool {
+	output, err := exec.Command("tmux", "show-options", "-gv", "allow-passthrough").Output()
+	if err != nil {
+		return true
+	}
+	return strings.TrimSpace(string(output)) != "off"
+}
+
+// WrapPassthrough wraps escape sequences meant for the outer terminal so the
+// multiplexer forwards them instead of interpreting (or swallowing) them
+// The multiplexer passes the wrapped bytes through verbatim, so the sequences
+// may be split into as many envelopes as needed.
+func WrapPassthrough(m types.Multiplexer, seq string) string {
+	switch m {
+	case types.MULTIPLEXER_TMUX:
+		return wrapTmux(seq)
+	case types.MULTIPLEXER_SCREEN:
+		return wrapScreen(seq)
+	default:
+		return seq
+	}
+}
+
+// wrapTmux wraps sequences in DCS tmux; ... ST envelopes, doubling every ESC
+// Needs allow-passthrough to be enabled in tmux 3.3 and later.
+func wrapTmux(seq string) string {
+	var sb strings.Builder
+	sb.Grow(len(seq) + len(seq)/tmuxChunkSize*10 + 16)
+
+	for start := 0; start < len(seq); start += tmuxChunkSize {
+		end := min(start+tmuxChunkSize, len(seq))
+		sb.WriteString("\033Ptmux;")
+		sb.WriteString(strings.ReplaceAll(seq[start:end], "\033", "\033\033"))
+		sb.WriteString("\033\\")
+	}
+	return sb.String()
+}
+
+// wrapScreen wraps sequences in DCS ... ST envelopes of at most
+// screenChunkSize bytes
+// Every envelope ends right after an ESC of the sequence: screen reads
+// ESC ESC \ as a literal ESC followed by the end of the envelope, so an ST
+// inside the sequence never closes the envelope early.
+func wrapScreen(seq string) string {
+	var sb strings.Builder
+	sb.Grow(len(seq) + len(seq)/screenChunkSize*4 + 16)
+
+	for start := 0; start < len(seq); {
+		end := min(start+screenChunkSize, len(seq))
+		if esc := strings.IndexByte(seq[start:end], '\033'); esc >= 0 {
+			end = start + esc + 1
+		}
+		sb.WriteString("\033P")
+		sb.WriteString(seq[start:end])
+		sb.WriteString("\033\\")
+		start = end
+	}
+	return sb.String()
+}
@@ -0,0 +1,123 @@
+package terminal
+
+import (
+	"strings"
+	"terminaltube/pkg/types"
+	"testing"
+)
+
+func TestWrapTmux(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"empty", "", ""},
+		{"plain text", "abc", "\033Ptmux;abc\033\\"},
+		{"ESC doubled", "\033[c", "\033Ptmux;\033\033[c\033\\"},
+		{
+			"ST inside the sequence is doubled too",
+			"\033_Ga=q;AAAA\033\\",
+			"\033Ptmux;\033\033_Ga=q;AAAA\033\033\\\033\\",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wrapTmux(tt.input); got != tt.want {
+				t.Errorf("wrapTmux(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapTmuxChunks(t *testing.T) {
+	seq := "\033P" + strings.Repeat("#", tmuxChunkSize*2) + "\033\\"
+	got := wrapTmux(seq)
+
+	// Unwrapping every envelope gives back the sequence
+	var unwrapped strings.Builder
+	chunks := 0
+	for rest := got; rest != ""; chunks++ {
+		body, ok := strings.CutPrefix(rest, "\033Ptmux;")
+		if !ok {
+			t.Fatalf("envelope %d doesn't start with DCS tmux;", chunks)
+		}
+		// The envelope ends at the first ST whose ESC isn't doubled
+		end := 0
+		for {
+			if body[end] == '\033' {
+				if body[end+1] == '\033' {
+					end += 2
+					continue
+				}
+				if body[end+1] == '\\' {
+					break
+				}
+			}
+			end++
+		}
+		unwrapped.WriteString(strings.ReplaceAll(body[:end], "\033\033", "\033"))
+		rest = body[end+2:]
+	}
+
+	if chunks != 3 {
+		t.Errorf("got %d envelopes, want 3", chunks)
+	}
+	if unwrapped.String() != seq {
+		t.Errorf("unwrapped sequence differs from the original")
+	}
+}
+
+func TestWrapScreen(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"empty", "", ""},
+		{"plain text", "abc", "\033Pabc\033\\"},
+		{
+			// Envelopes end right after each ESC, so the sequence's ST never
+			// closes one early
+			"ends after each ESC",
+			"\033[c",
+			"\033P\033\033\\\033P[c\033\\",
+		},
+		{
+			"ST inside the sequence",
+			"a\033\\b",
+			"\033Pa\033\033\\\033P\\b\033\\",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wrapScreen(tt.input); got != tt.want {
+				t.Errorf("wrapScreen(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapScreenChunkSize(t *testing.T) {
+	seq := strings.Repeat("#", screenChunkSize*2+10)
+	got := wrapScreen(seq)
+
+	envelopes := strings.Split(strings.TrimSuffix(got, "\033\\"), "\033\\")
+	if len(envelopes) != 3 {
+		t.Fatalf("got %d envelopes, want 3", len(envelopes))
+	}
+	for i, envelope := range envelopes {
+		body := strings.TrimPrefix(envelope, "\033P")
+		if len(body) > screenChunkSize {
+			t.Errorf("envelope %d holds %d bytes, more than %d", i, len(body), screenChunkSize)
+		}
+	}
+}
+
+func TestWrapPassthroughNone(t *testing.T) {
+	if got := WrapPassthrough(types.MULTIPLEXER_NONE, "\033[c"); got != "\033[c" {
+		t.Errorf("WrapPassthrough(NONE) = %q, want the sequence unchanged", got)
+	}
+}
@@ -0,0 +1,184 @@
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"terminaltube/pkg/types"
+	"time"
+
+	"github.com/muesli/cancelreader"
+	"golang.org/x/term"
+)
+
+// queryTimeout bounds how long DetectCapabilities waits for the terminal to
+// answer its queries; terminals that answer at all do so within milliseconds
+const queryTimeout = 500 * time.Millisecond
+
+// queryDA1 is the primary device attributes request
+// Every terminal answers it, so it is sent last and its reply marks the end
+// of the replies to everything sent before it.
+const queryDA1 = "\033[c"
+
+// Kinds of terminal replies, named by the byte following ESC
+const (
+	replyCSI = '['
+	replyOSC = ']'
+	replyAPC = '_'
+)
+
+// terminalReply is one control sequence received from the terminal
+type terminalReply struct {
+	kind   byte   // replyCSI, replyOSC or replyAPC
+	params string // Parameter bytes, including any '?' or '>' prefix; the payload of OSC and APC
+	final  string // Intermediate and final bytes of a CSI ("c", "t", "$y"); empty otherwise
+}
+
+// queryReplies are the replies to one round of queries
+type queryReplies []terminalReply
+
+// csi returns the parameters of the first CSI reply with the given prefix and
+// final bytes, without the prefix
+func (q queryReplies) csi(prefix, final string) ([]int, bool) {
+	for _, reply := range q {
+		if reply.kind != replyCSI || reply.final != final || !strings.HasPrefix(reply.params, prefix) {
+			continue
+		}
+		return parseParams(strings.TrimPrefix(reply.params, prefix)), true
+	}
+	return nil, false
+}
+
+// osc returns the value of the first OSC reply for the given command number
+func (q queryReplies) osc(command int) (string, bool) {
+	return q.payload(replyOSC, strconv.Itoa(command)+";")
+}
+
+// apc returns the rest of the first APC reply starting with prefix
+func (q queryReplies) apc(prefix string) (string, bool) {
+	return q.payload(replyAPC, prefix)
+}
+
+// payload returns the rest of the first string reply of the given kind that
+// starts with prefix
+func (q queryReplies) payload(kind byte, prefix string) (string, bool) {
+	for _, reply := range q {
+		if reply.kind == kind && strings.HasPrefix(reply.params, prefix) {
+			return strings.TrimPrefix(reply.params, prefix), true
+		}
+	}
+	return "", false
+}
+
+// queryTerminal writes queries followed by a DA1 request and collects the
+// terminal's replies until the DA1 answer arrives or the timeout expires
+// Input is switched to unbuffered, no-echo mode meanwhile so replies are
+// neither shown nor held back until Enter. Inside a multiplexer the queries
+// are passed through to the outer terminal, which knows its graphics support.
+func queryTerminal(queries string, multiplexer types.Multiplexer, timeout time.Duration) (queryReplies, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return nil, fmt.Errorf("not running in a terminal")
+	}
+
+	restore, err := enableKeyInput(fd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enable raw input: %w", err)
+	}
+	defer restore()
+
+	reader, err := cancelreader.NewReader(os.Stdin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdin reader: %w", err)
+	}
+	defer reader.Close()
+
+	if _, err := os.Stdout.WriteString(WrapPassthrough(multiplexer, queries+queryDA1)); err != nil {
+		return nil, fmt.Errorf("failed to send queries: %w", err)
+	}
+
+	timer := time.AfterFunc(timeout, func() { reader.Cancel() })
+	defer timer.Stop()
+
+	var received []byte
+	buf := make([]byte, 256)
+	for {
+		n, err := reader.Read(buf)
+		received = append(received, buf[:n]...)
+
+		replies := parseReplies(received)
+		if _, ok := replies.csi("?", "c"); ok {
+			return replies, nil
+		}
+		if err != nil {
+			// Timed out: keep whatever did arrive
+			if len(replies) > 0 {
+				return replies, nil
+			}
+			return nil, fmt.Errorf("terminal did not answer queries: %w", err)
+		}
+	}
+}
+
+// parseReplies splits received bytes into CSI, OSC and APC sequences,
+// skipping anything else (such as keys typed meanwhile)
+func parseReplies(data []byte) queryReplies {
+	var replies queryReplies
+
+	for i := 0; i < len(data); i++ {
+		if data[i] != 0x1b || i+1 >= len(data) {
+			continue
+		}
+
+		switch data[i+1] {
+		case replyCSI:
+			// Parameters 0x30-0x3F, intermediates 0x20-0x2F, final 0x40-0x7E
+			start := i + 2
+			end := start
+			for end < len(data) && data[end] >= 0x30 && data[end] <= 0x3f {
+				end++
+			}
+			finalStart := end
+			for end < len(data) && data[end] >= 0x20 && data[end] <= 0x2f {
+				end++
+			}
+			if end >= len(data) || data[end] < 0x40 || data[end] > 0x7e {
+				continue
+			}
+			replies = append(replies, terminalReply{
+				kind:   replyCSI,
+				params: string(data[start:finalStart]),
+				final:  string(data[finalStart : end+1]),
+			})
+			i = end
+		case replyOSC, replyAPC:
+			// Terminated by BEL or ST (ESC \)
+			start := i + 2
+			for end := start; end < len(data); end++ {
+				if data[end] == 0x07 || (data[end] == 0x1b && end+1 < len(data) && data[end+1] == '\\') {
+					replies = append(replies, terminalReply{kind: data[i+1], params: string(data[start:end])})
+					i = end
+					break
+				}
+			}
+		}
+	}
+
+	return replies
+}
+
+// parseParams splits semicolon-separated numeric parameters; empty or
+// non-numeric parameters read as 0
+func parseParams(params string) []int {
+	if params == "" {
+		return nil
+	}
+
+	fields := strings.Split(params, ";")
+	values := make([]int, len(fields))
+	for i, field := range fields {
+		values[i], _ = strconv.Atoi(field)
+	}
+	return values
+}
@@ -0,0 +1,151 @@
+package terminal
+
+import (
+	"reflect"
+	"terminaltube/pkg/types"
+	"testing"
+)
+
+func TestParseReplies(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  queryReplies
+	}{
+		{
+			"DA1",
+			"\033[?62;4;22c",
+			queryReplies{{kind: replyCSI, params: "?62;4;22", final: "c"}},
+		},
+		{
+			"XTSMGRAPHICS color registers and geometry",
+			"\033[?1;0;256S\033[?2;0;1000;800S",
+			queryReplies{
+				{kind: replyCSI, params: "?1;0;256", final: "S"},
+				{kind: replyCSI, params: "?2;0;1000;800", final: "S"},
+			},
+		},
+		{
+			"DECRQM with intermediate",
+			"\033[?2026;2$y",
+			queryReplies{{kind: replyCSI, params: "?2026;2", final: "$y"}},
+		},
+		{
+			"OSC terminated by ST and BEL",
+			"\033]11;rgb:ffff/ffff/ffff\033\\\033]10;rgb:00/00/00\007",
+			queryReplies{
+				{kind: replyOSC, params: "11;rgb:ffff/ffff/ffff"},
+				{kind: replyOSC, params: "10;rgb:00/00/00"},
+			},
+		},
+		{
+			"Kitty APC",
+			"\033_Gi=31;OK\033\\",
+			queryReplies{{kind: replyAPC, params: "Gi=31;OK"}},
+		},
+		{
+			"typed keys between replies are skipped",
+			"ab\033[4;600;800tq\033[?1;2c",
+			queryReplies{
+				{kind: replyCSI, params: "4;600;800", final: "t"},
+				{kind: replyCSI, params: "?1;2", final: "c"},
+			},
+		},
+		{"truncated CSI", "\033[?62;4", nil},
+		{"unterminated OSC", "\033]11;rgb:ffff/ffff", nil},
+		{"lone ESC", "\033", nil},
+		{"invalid CSI byte", "\033[?6\x01c", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseReplies([]byte(tt.input))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseReplies(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseParams(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []int
+	}{
+		{"", nil},
+		{"62", []int{62}},
+		{"1;0;256", []int{1, 0, 256}},
+		{"4;;800", []int{4, 0, 800}},
+		{"2026;x", []int{2026, 0}},
+	}
+
+	for _, tt := range tests {
+		if got := parseParams(tt.input); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseParams(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestQueryRepliesLookup(t *testing.T) {
+	replies := parseReplies([]byte("\033[?1;0;256S\033[?2;0;1000;800S\033]11;rgb:1e1e/1e1e/2e2e\033\\\033[?62;4c"))
+
+	if params, ok := replies.csi("?2;", "S"); !ok || !reflect.DeepEqual(params, []int{0, 1000, 800}) {
+		t.Errorf("csi(?2;, S) = %v, %v", params, ok)
+	}
+	if params, ok := replies.csi("?", "c"); !ok || !reflect.DeepEqual(params, []int{62, 4}) {
+		t.Errorf("csi(?, c) = %v, %v", params, ok)
+	}
+	if _, ok := replies.csi("?", "t"); ok {
+		t.Errorf("csi(?, t) found a reply that wasn't sent")
+	}
+	if value, ok := replies.osc(11); !ok || value != "rgb:1e1e/1e1e/2e2e" {
+		t.Errorf("osc(11) = %q, %v", value, ok)
+	}
+	if _, ok := replies.osc(10); ok {
+		t.Errorf("osc(10) found a reply that wasn't sent")
+	}
+}
+
+func TestDetectGraphicsFromReplies(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		wantSixel     bool
+		wantKitty     bool
+		wantRegisters int
+		wantMaxWidth  int
+		wantMaxHeight int
+	}{
+		{"SIXEL attribute", "\033[?62;4;22c", true, false, 0, 0, 0},
+		{"no SIXEL attribute", "\033[?62;22c", false, false, 0, 0, 0},
+		{"conformance level 4 is not SIXEL", "\033[?4;22c", false, false, 0, 0, 0},
+		{
+			"XTSMGRAPHICS limits",
+			"\033[?1;0;1024S\033[?2;0;1920;1080S\033[?62;4c",
+			true, false, 1024, 1920, 1080,
+		},
+		{"XTSMGRAPHICS error status", "\033[?1;3;0S\033[?2;1S\033[?62;4c", true, false, 0, 0, 0},
+		{"Kitty reply", "\033_Gi=31;OK\033\\\033[?62;22c", false, true, 0, 0, 0},
+		{"Kitty error reply", "\033_Gi=31;EINVAL:bad\033\\\033[?62;22c", false, true, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var capabilities types.TerminalCapabilities
+			detectGraphics(&capabilities, parseReplies([]byte(tt.input)))
+
+			if capabilities.SixelSupport != tt.wantSixel || capabilities.SixelMethod != types.DETECTION_QUERY {
+				t.Errorf("SIXEL = %v (%s), want %v (QUERY)", capabilities.SixelSupport, capabilities.SixelMethod, tt.wantSixel)
+			}
+			if capabilities.KittyGraphics != tt.wantKitty || capabilities.KittyMethod != types.DETECTION_QUERY {
+				t.Errorf("Kitty = %v (%s), want %v (QUERY)", capabilities.KittyGraphics, capabilities.KittyMethod, tt.wantKitty)
+			}
+			if capabilities.SixelColorRegisters != tt.wantRegisters ||
+				capabilities.SixelMaxWidth != tt.wantMaxWidth || capabilities.SixelMaxHeight != tt.wantMaxHeight {
+				t.Errorf("SIXEL limits = %d, %dx%d, want %d, %dx%d",
+					capabilities.SixelColorRegisters, capabilities.SixelMaxWidth, capabilities.SixelMaxHeight,
+					tt.wantRegisters, tt.wantMaxWidth, tt.wantMaxHeight)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// resizeSettleTime is how long the size must stay put before a resize is
+// reported, so dragging a window edge produces one event rather than dozens
+const resizeSettleTime = 100 * time.Millisecond
+
+// WatchResize reports terminal resizes on the returned channel until stop is
+// called
+// Events carry no size; read it with GetTerminalSize (and GetCellSize, since
+// changing the font size also resizes the terminal).
+func WatchResize() (<-chan struct{}, func()) {
+	events := make(chan struct{}, 1)
+	signals := make(chan struct{}, 1)
+	done := make(chan struct{})
+
+	stopSignals := notifyResize(signals)
+
+	go func() {
+		settle := time.NewTimer(resizeSettleTime)
+		settle.Stop()
+		defer settle.Stop()
+
+		for {
+			select {
+			case <-signals:
+				settle.Reset(resizeSettleTime)
+			case <-settle.C:
+				// Coalesce with an event nobody has picked up yet
+				select {
+				case events <- struct{}{}:
+				default:
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return events, func() {
+		stopSignals()
+		close(done)
+	}
+}
+
+// GetCellSize returns the current pixel size of a character cell as the
+// terminal driver reports it
+func GetCellSize() (int, int, error) {
+	cols, rows, width, height, err := windowPixelSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return 0, 0, err
+	}
+	if cols <= 0 || rows <= 0 {
+		return 0, 0, fmt.Errorf("terminal reports no size")
+	}
+	return width / cols, height / rows, nil
+}
@@ -0,0 +1,40 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package terminal
+
+import "time"
+
+// resizePollInterval is how often the console size is checked where there is
+// no resize signal
+const resizePollInterval = 250 * time.Millisecond
+
+// notifyResize signals on resized whenever the console size changes, until
+// the returned function is called
+func notifyResize(resized chan<- struct{}) func() {
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(resizePollInterval)
+		defer ticker.Stop()
+
+		width, height, _ := GetTerminalSize()
+		for {
+			select {
+			case <-ticker.C:
+				newWidth, newHeight, err := GetTerminalSize()
+				if err != nil || (newWidth == width && newHeight == height) {
+					continue
+				}
+				width, height = newWidth, newHeight
+				select {
+				case resized <- struct{}{}:
+				default:
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
@@ -1,32 +1,46 @@
 package terminal
 
-// This file contains utilities for terminal size detection.
-// The main GetTerminalSize function is implemented in capability.go
-// to keep all terminal detection logic in one place.
+import (
+	"os"
+	"terminaltube/pkg/types"
+)
 
-// Note: For full functionality, this would use golang.org/x/term
-// which requires CGO on some platforms. The implementation above
-// provides a working fallback that uses environment variables.
-//
-// Full implementation would look like:
-/*
-import "golang.org/x/term"
+// Queries for the cell size (XTWINOPS 16, answered with CSI 6;h;w t) and,
+// for terminals that only know their text area, its pixel size (XTWINOPS 14,
+// answered with CSI 4;h;w t)
+const (
+	queryCellSize     = "\033[16t"
+	queryTextAreaSize = "\033[14t"
+)
 
-func GetTerminalSize() (int, int, error) {
-	width, height, err := term.GetSize(int(os.Stdin.Fd()))
-	if err != nil {
-		// Fallback to environment variables
-		if w := os.Getenv("COLUMNS"); w != "" {
-			if width, err := strconv.Atoi(w); err == nil {
-				if h := os.Getenv("LINES"); h != "" {
-					if height, err := strconv.Atoi(h); err == nil {
-						return width, height, nil
-					}
-				}
-			}
+// detectCellSize finds the pixel size of a character cell
+// The terminal driver's answer is preferred; the replies to the XTWINOPS
+// queries are used when the driver doesn't know.
+func detectCellSize(cols, rows int, replies queryReplies) (int, int, types.DetectionMethod) {
+	for _, f := range []*os.File{os.Stdout, os.Stdin} {
+		wsCols, wsRows, width, height, err := windowPixelSize(int(f.Fd()))
+		if err == nil && wsCols > 0 && wsRows > 0 && width >= wsCols && height >= wsRows {
+			return width / wsCols, height / wsRows, types.DETECTION_IOCTL
 		}
-		return 80, 24, err
 	}
-	return width, height, nil
+
+	if width, height, ok := cellSizeFromReplies(replies, cols, rows); ok {
+		return width, height, types.DETECTION_QUERY
+	}
+	return 0, 0, types.DETECTION_NONE
+}
+
+// cellSizeFromReplies reads the cell size from XTWINOPS replies, preferring
+// the cell size report over dividing up the text area
+func cellSizeFromReplies(replies queryReplies, cols, rows int) (int, int, bool) {
+	if params, ok := replies.csi("6;", "t"); ok && len(params) == 2 && params[0] > 0 && params[1] > 0 {
+		return params[1], params[0], true
+	}
+
+	if params, ok := replies.csi("4;", "t"); ok && len(params) == 2 && cols > 0 && rows > 0 &&
+		params[0] >= rows && params[1] >= cols {
+		return params[1] / cols, params[0] / rows, true
+	}
+
+	return 0, 0, false
 }
-*/
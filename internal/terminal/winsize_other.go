@@ -0,0 +1,11 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package terminal
+
+import "fmt"
+
+// windowPixelSize is unavailable without TIOCGWINSZ; the pixel size is
+// queried from the terminal instead
+func windowPixelSize(fd int) (cols, rows, width, height int, err error) {
+	return 0, 0, 0, 0, fmt.Errorf("window pixel size not supported on this platform")
+}
@@ -0,0 +1,23 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package terminal
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// windowPixelSize returns the text area's size in cells and pixels as the
+// terminal driver records it
+// Terminals that don't fill in the pixel fields leave them 0.
+func windowPixelSize(fd int) (cols, rows, width, height int, err error) {
+	ws, err := unix.IoctlGetWinsize(fd, unix.TIOCGWINSZ)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	if ws.Xpixel == 0 || ws.Ypixel == 0 {
+		return int(ws.Col), int(ws.Row), 0, 0, fmt.Errorf("terminal reports no pixel size")
+	}
+	return int(ws.Col), int(ws.Row), int(ws.Xpixel), int(ws.Ypixel), nil
+}
@@ -146,7 +146,7 @@ func (h *HeaderAnimation) renderWithScramble() string {
 				if r == ' ' || r == '\n' {
 					result.WriteRune(r)
 				} else {
-					style := lipgloss.NewStyle().Foreground(lipgloss.Color("#333333"))
+					style := lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Dark: "#333333", Light: "#DDDDDD"})
 					result.WriteString(style.Render("░"))
 				}
 			}
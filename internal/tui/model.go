@@ -369,6 +369,7 @@ func (m Model) renderCapabilityBadges() string {
 		Render(fmt.Sprintf("Terminal: %dx%d", m.capabilities.Width, m.capabilities.Height)))
 
 	badges = append(badges, Badge("SIXEL", m.capabilities.SixelSupport))
+	badges = append(badges, Badge("Kitty", m.capabilities.KittyGraphics))
 	badges = append(badges, Badge("TrueColor", m.capabilities.TrueColor))
 	badges = append(badges, Badge("Unicode", m.capabilities.UnicodeSupport))
 
@@ -384,15 +385,28 @@ func (m Model) renderTerminalInfo() string {
 	s.WriteString(lipgloss.NewStyle().Align(lipgloss.Center).Width(m.width).Render(title))
 	s.WriteString("\n\n")
 
-	info := []struct {
+	type infoRow struct {
 		key   string
 		value string
-	}{
+	}
+	info := []infoRow{
 		{"Terminal Size", fmt.Sprintf("%d x %d", m.capabilities.Width, m.capabilities.Height)},
-		{"SIXEL Support", fmt.Sprintf("%v", m.capabilities.SixelSupport)},
+		{"Cell Size", cellSizeInfo(m.capabilities)},
+		{"Multiplexer", multiplexerInfo(m.capabilities)},
+		{"SIXEL Support", fmt.Sprintf("%v (%s)", m.capabilities.SixelSupport, m.capabilities.SixelMethod)},
+		{"Kitty Graphics", fmt.Sprintf("%v (%s)", m.capabilities.KittyGraphics, m.capabilities.KittyMethod)},
+		{"iTerm2 Images", fmt.Sprintf("%v (%s)", m.capabilities.ITerm2Images, m.capabilities.ITerm2Method)},
 		{"True Color (24-bit)", fmt.Sprintf("%v", m.capabilities.TrueColor)},
 		{"256 Colors", fmt.Sprintf("%v", m.capabilities.Color256)},
 		{"Unicode Support", fmt.Sprintf("%v", m.capabilities.UnicodeSupport)},
+		{"Synchronized Output", fmt.Sprintf("%v", m.capabilities.SynchronizedOutput)},
+		{"Theme Colors", themeColorInfo(m.capabilities)},
+	}
+	if m.capabilities.SixelColorRegisters > 0 {
+		info = append(info, infoRow{"SIXEL Colors", fmt.Sprintf("%d", m.capabilities.SixelColorRegisters)})
+	}
+	if m.capabilities.SixelMaxWidth > 0 && m.capabilities.SixelMaxHeight > 0 {
+		info = append(info, infoRow{"SIXEL Max Size", fmt.Sprintf("%d x %d", m.capabilities.SixelMaxWidth, m.capabilities.SixelMaxHeight)})
 	}
 
 	for _, item := range info {
@@ -408,6 +422,35 @@ func (m Model) renderTerminalInfo() string {
 	return s.String()
 }
 
+// multiplexerInfo describes the multiplexer, if any, and whether graphics
+// pass through it
+func multiplexerInfo(capabilities types.TerminalCapabilities) string {
+	name := strings.ToLower(capabilities.Multiplexer.String())
+	if capabilities.Multiplexer != types.MULTIPLEXER_NONE && capabilities.Passthrough == types.MULTIPLEXER_NONE {
+		return name + " (passthrough disabled)"
+	}
+	return name
+}
+
+// cellSizeInfo describes the detected cell pixel size and how it was found
+func cellSizeInfo(capabilities types.TerminalCapabilities) string {
+	if capabilities.CellWidth == 0 || capabilities.CellHeight == 0 {
+		return "unknown"
+	}
+	return fmt.Sprintf("%d x %d px (%s)", capabilities.CellWidth, capabilities.CellHeight, capabilities.CellSizeMethod)
+}
+
+// themeColorInfo describes the terminal's default colors and how they were found
+func themeColorInfo(capabilities types.TerminalCapabilities) string {
+	fg, bg := capabilities.ForegroundColor, capabilities.BackgroundColor
+	theme := "dark"
+	if capabilities.LightBackground {
+		theme = "light"
+	}
+	return fmt.Sprintf("%s, fg #%02x%02x%02x on bg #%02x%02x%02x (%s)",
+		theme, fg[0], fg[1], fg[2], bg[0], bg[1], bg[2], capabilities.ColorMethod)
+}
+
 // renderInputView renders input prompt views
 func (m Model) renderInputView() string {
 	var s strings.Builder
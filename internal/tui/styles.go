@@ -4,26 +4,34 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-// Color palette - modern gradient purples/cyans for media player aesthetic
+// Color palette - modern gradient purples/cyans for media player aesthetic,
+// with darker variants for terminals with a light background
 var (
 	// Primary gradient colors
-	PrimaryColor   = lipgloss.Color("#BD93F9")
-	SecondaryColor = lipgloss.Color("#8BE9FD")
-	AccentColor    = lipgloss.Color("#FF79C6")
-	SuccessColor   = lipgloss.Color("#50FA7B")
-	WarningColor   = lipgloss.Color("#FFB86C")
-	ErrorColor     = lipgloss.Color("#FF5555")
+	PrimaryColor   = lipgloss.AdaptiveColor{Dark: "#BD93F9", Light: "#644AC9"}
+	SecondaryColor = lipgloss.AdaptiveColor{Dark: "#8BE9FD", Light: "#036A96"}
+	AccentColor    = lipgloss.AdaptiveColor{Dark: "#FF79C6", Light: "#A3144D"}
+	SuccessColor   = lipgloss.AdaptiveColor{Dark: "#50FA7B", Light: "#14710A"}
+	WarningColor   = lipgloss.AdaptiveColor{Dark: "#FFB86C", Light: "#A34D14"}
+	ErrorColor     = lipgloss.AdaptiveColor{Dark: "#FF5555", Light: "#CB3A2A"}
 
 	// Text colors
-	TextColor      = lipgloss.Color("#F8F8F2")
-	SubtleColor    = lipgloss.Color("#6272A4")
-	HighlightColor = lipgloss.Color("#F1FA8C")
+	TextColor      = lipgloss.AdaptiveColor{Dark: "#F8F8F2", Light: "#1F1F1F"}
+	SubtleColor    = lipgloss.AdaptiveColor{Dark: "#6272A4", Light: "#6C664B"}
+	HighlightColor = lipgloss.AdaptiveColor{Dark: "#F1FA8C", Light: "#846E15"}
 
 	// Background colors
-	BackgroundColor = lipgloss.Color("#282A36")
-	SurfaceColor    = lipgloss.Color("#44475A")
+	BackgroundColor = lipgloss.AdaptiveColor{Dark: "#282A36", Light: "#FFFBEB"}
+	SurfaceColor    = lipgloss.AdaptiveColor{Dark: "#44475A", Light: "#CFCFDE"}
 )
 
+// SetLightBackground picks the light or dark variant of the palette
+// It replaces lipgloss's own detection with the result of DetectCapabilities,
+// which has already asked the terminal.
+func SetLightBackground(light bool) {
+	lipgloss.SetHasDarkBackground(!light)
+}
+
 // Styles for the TUI
 
 // Title style for the main header
@@ -34,7 +42,7 @@ var TitleStyle = lipgloss.NewStyle().
 // Gradient title with animation effect
 var GradientTitleStyle = lipgloss.NewStyle().
 	Bold(true).
-	Foreground(lipgloss.Color("#BD93F9"))
+	Foreground(PrimaryColor)
 
 // Subtitle style
 var SubtitleStyle = lipgloss.NewStyle().
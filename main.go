@@ -2,7 +2,10 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
+	"image"
+	"math"
 	"os"
 	"os/signal"
 	"strconv"
@@ -16,81 +19,220 @@ import (
 	"terminaltube/internal/tui"
 	"terminaltube/pkg/types"
 	"time"
+	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-// calculateOptimalRenderSize calculates the best render dimensions for the current terminal
-// For SIXEL mode, this returns character dimensions (will be multiplied by pixel ratio later)
-func calculateOptimalRenderSize(imgWidth, imgHeight, termWidth, termHeight int) (int, int) {
-	// Use full terminal width, minimal height margin for status
-	maxWidth := termWidth
-	maxHeight := termHeight - 2 // Leave 2 rows for any UI
+// frameLayout says where a frame goes on the terminal and which part of the
+// source it shows
+type frameLayout struct {
+	width, height int // Frame size in character cells
+	column, row   int // 0-based cell position of the frame's top-left corner
+
+	// Visible part of the source, as fractions of its width and height
+	cropX, cropY, cropWidth, cropHeight float64
+}
 
-	// Ensure minimum available space
-	if maxWidth < 10 {
-		maxWidth = 10
+// cellAspect returns the height-to-width ratio of a character cell in the
+// pixels the options' mode samples
+func cellAspect(options types.RenderOptions) float64 {
+	switch {
+	case options.Mode == types.SIXEL || options.Mode == types.KITTY || options.Mode == types.ITERM2:
+		cellWidth, cellHeight := renderer.CellSampling(options)
+		return float64(cellHeight) / float64(cellWidth)
+	case options.CellPixelWidth > 0 && options.CellPixelHeight > 0:
+		return float64(options.CellPixelHeight) / float64(options.CellPixelWidth)
+	default:
+		// A character is typically about 2x taller than wide
+		return 2.0
 	}
-	if maxHeight < 5 {
-		maxHeight = 5
+}
+
+// calculateOptimalRenderSize lays out media of the given pixel size on the
+// terminal according to the options' fit mode and render mode
+// The frame is centered in the space above the status rows, and whatever
+// overflows that space is cropped evenly from both sides.
+func calculateOptimalRenderSize(imgWidth, imgHeight, termWidth, termHeight int, options types.RenderOptions) frameLayout {
+	width, height := pictureSize(imgWidth, imgHeight, termWidth, termHeight, options)
+	return placeFrame(width, height, 0.5, 0.5, termWidth, termHeight, options)
+}
+
+// frameArea returns the cells available to a frame: the full terminal width
+// and all but the status rows
+func frameArea(termWidth, termHeight int) (int, int) {
+	// Use full terminal width, minimal height margin for status
+	return max(termWidth, 10), max(termHeight-2, 5) // Leave 2 rows for any UI
+}
+
+// maxFrameSize returns the largest frame in cells: the frame area, limited
+// to the biggest image the terminal draws in SIXEL mode
+func maxFrameSize(termWidth, termHeight int, options types.RenderOptions) (int, int) {
+	width, height := frameArea(termWidth, termHeight)
+
+	if options.Mode == types.SIXEL {
+		cellWidth, cellHeight := renderer.CellSampling(options)
+		if options.SixelMaxWidth > 0 {
+			width = max(1, min(width, options.SixelMaxWidth/cellWidth))
+		}
+		if options.SixelMaxHeight > 0 {
+			height = max(1, min(height, options.SixelMaxHeight/cellHeight))
+		}
 	}
+	return width, height
+}
 
-	// Calculate image aspect ratio
+// pictureSize returns the size in cells of the whole picture under the
+// options' fit mode, which may be larger than the terminal
+func pictureSize(imgWidth, imgHeight, termWidth, termHeight int, options types.RenderOptions) (float64, float64) {
+	maxWidth, maxHeight := maxFrameSize(termWidth, termHeight, options)
 	imgAspect := float64(imgWidth) / float64(imgHeight)
+	charAspect := cellAspect(options)
+
+	var width, height float64
+	switch options.Fit {
+	case types.FIT_COVER:
+		width = float64(maxWidth)
+		height = width / imgAspect / charAspect
+		if height < float64(maxHeight) {
+			height = float64(maxHeight)
+			width = height * imgAspect * charAspect
+		}
+	case types.FIT_STRETCH:
+		width, height = float64(maxWidth), float64(maxHeight)
+	case types.FIT_ORIGINAL:
+		// One source pixel per sampled pixel across
+		cellWidth, _ := renderer.CellSampling(options)
+		width = float64(imgWidth) / float64(cellWidth)
+		height = width / imgAspect / charAspect
+	case types.FIT_WIDTH:
+		width = float64(maxWidth)
+		height = width / imgAspect / charAspect
+	default:
+		// Fit to width first, then to height if that is too tall
+		width = float64(maxWidth)
+		height = width / imgAspect / charAspect
+		if height > float64(maxHeight) {
+			height = float64(maxHeight)
+			width = height * imgAspect * charAspect
+		}
+	}
 
-	// For terminal characters, they are ~2x tall as wide
-	// But for SIXEL (pixel-based), we'll handle this in the pixel conversion
-	// Here we just calculate character cell dimensions
+	return width, height
+}
 
-	// Terminal character aspect ratio (height/width of a character cell)
-	// A character is typically about 2x taller than wide
-	charAspect := 2.0
+// placeFrame lays out a picture of width x height cells, showing as much of
+// it as fits around the point (centerX, centerY), given as fractions of the
+// picture's size
+// The crop is kept inside the picture and the frame is centered on screen.
+func placeFrame(width, height, centerX, centerY float64, termWidth, termHeight int, options types.RenderOptions) frameLayout {
+	maxWidth, maxHeight := maxFrameSize(termWidth, termHeight, options)
+	_, areaHeight := frameArea(termWidth, termHeight)
+
+	layout := frameLayout{
+		width:  min(max(int(math.Round(width)), 1), maxWidth),
+		height: min(max(int(math.Round(height)), 1), maxHeight),
+	}
+	layout.cropWidth = math.Min(1, float64(layout.width)/width)
+	layout.cropHeight = math.Min(1, float64(layout.height)/height)
+	layout.cropX = math.Max(0, math.Min(1-layout.cropWidth, centerX-layout.cropWidth/2))
+	layout.cropY = math.Max(0, math.Min(1-layout.cropHeight, centerY-layout.cropHeight/2))
+	layout.column = max(termWidth-layout.width, 0) / 2
+	layout.row = (areaHeight - layout.height) / 2
+
+	return layout
+}
 
-	// Calculate render dimensions to fill available space while maintaining aspect
-	// Try fitting to width first
-	renderWidth := maxWidth
-	renderHeight := int(float64(renderWidth) / imgAspect / charAspect)
+// apply sets the frame size and position in options
+func (l frameLayout) apply(options *types.RenderOptions) {
+	options.Width = l.width
+	options.Height = l.height
+	options.OriginColumn = l.column
+	options.OriginRow = l.row
+}
 
-	// If height exceeds max, fit to height instead
-	if renderHeight > maxHeight {
-		renderHeight = maxHeight
-		renderWidth = int(float64(renderHeight) * imgAspect * charAspect)
-	}
+// cropped reports whether only part of the source is shown
+func (l frameLayout) cropped() bool {
+	return l.cropWidth < 1 || l.cropHeight < 1
+}
 
-	// Ensure we don't exceed terminal bounds
-	if renderWidth > maxWidth {
-		renderWidth = maxWidth
+// crop returns the visible part of a frame
+// The crop is relative, so it applies to frames the video decoder has
+// already scaled as well as to the source itself.
+func (l frameLayout) crop(img image.Image) image.Image {
+	if !l.cropped() {
+		return img
 	}
-	if renderHeight > maxHeight {
-		renderHeight = maxHeight
+	sub, ok := img.(interface {
+		SubImage(image.Rectangle) image.Image
+	})
+	if !ok {
+		return img
 	}
 
-	// Ensure minimum sizes
-	if renderWidth < 10 {
-		renderWidth = 10
+	bounds := img.Bounds()
+	x0 := bounds.Min.X + int(l.cropX*float64(bounds.Dx()))
+	y0 := bounds.Min.Y + int(l.cropY*float64(bounds.Dy()))
+	x1 := x0 + max(1, int(math.Round(l.cropWidth*float64(bounds.Dx()))))
+	y1 := y0 + max(1, int(math.Round(l.cropHeight*float64(bounds.Dy()))))
+	return sub.SubImage(image.Rect(x0, y0, x1, y1).Intersect(bounds))
+}
+
+// decodeSize returns the size the video decoder should scale whole frames to
+// so the visible part comes out at the renderer's sampling resolution
+func (l frameLayout) decodeSize(options types.RenderOptions) (int, int) {
+	width, height := renderer.FramePixelSize(options)
+	width = int(math.Round(float64(width) / l.cropWidth))
+	height = int(math.Round(float64(height) / l.cropHeight))
+	return width, height
+}
+
+// clearScreen clears the terminal, painting the letterbox color around
+// frames when one is set
+func clearScreen(termControl *terminal.Control, options types.RenderOptions) {
+	if options.Letterbox {
+		termControl.FillScreen(options.LetterboxSGR)
+		return
 	}
-	if renderHeight < 5 {
-		renderHeight = 5
+	termControl.ClearScreen()
+}
+
+// refreshTerminalSize re-reads the terminal and cell size after a resize,
+// reporting whether either changed
+func refreshTerminalSize(capabilities *types.TerminalCapabilities, options *types.RenderOptions) bool {
+	width, height, err := getCurrentTerminalSize()
+	if err != nil {
+		width, height = 0, 0
 	}
 
-	return renderWidth, renderHeight
+	// Changing the font size resizes the cells, and not always the grid
+	cellWidth, cellHeight := 0, 0
+	if options.CellPixelWidth > 0 && options.CellPixelHeight > 0 {
+		if w, h, err := terminal.GetCellSize(); err == nil {
+			cellWidth, cellHeight = w, h
+		}
+	}
+	return updateTerminalSize(capabilities, options, width, height, cellWidth, cellHeight)
 }
 
-// calculateOptimalRenderSizeSixel calculates dimensions for SIXEL mode
-// SIXEL renders in actual pixels - we fill the entire terminal viewport
-// Video is stretched to fit (no aspect ratio preservation for maximum coverage)
-func calculateOptimalRenderSizeSixel(imgWidth, imgHeight, termWidth, termHeight int) (int, int) {
-	// Use full terminal dimensions
-	// Width: 10 pixels per char for full width coverage
-	// Height: 19 pixels per char to avoid exceeding viewport and causing scroll, got that happening during Windows Terminal Testing
-	pixelWidth := termWidth * 10
-	pixelHeight := termHeight * 19
-
-	// Make height divisible by 6 (SIXEL requirement form docs)
-	pixelHeight = (pixelHeight / 6) * 6
-
-	// Return full terminal pixel dimensions
-	return pixelWidth, pixelHeight
+// updateTerminalSize records a new grid and cell size, skipping sizes that
+// are unknown (zero), and reports whether either changed
+func updateTerminalSize(capabilities *types.TerminalCapabilities, options *types.RenderOptions, width, height, cellWidth, cellHeight int) bool {
+	changed := false
+	if width > 0 && height > 0 &&
+		(width != capabilities.Width || height != capabilities.Height) {
+		capabilities.Width, capabilities.Height = width, height
+		changed = true
+	}
+
+	if cellWidth > 0 && cellHeight > 0 &&
+		(cellWidth != options.CellPixelWidth || cellHeight != options.CellPixelHeight) {
+		options.CellPixelWidth, options.CellPixelHeight = cellWidth, cellHeight
+		options.TerminalAspectRatio = float64(cellWidth) / float64(cellHeight)
+		capabilities.CellWidth, capabilities.CellHeight = cellWidth, cellHeight
+		changed = true
+	}
+	return changed
 }
 
 // getCurrentTerminalSize gets the current terminal size (for dynamic resizing)
@@ -103,7 +245,152 @@ const (
 	appVersion = "1.0.0"
 )
 
+// renderDefaults holds the render options every handler starts from,
+// adjusted by command-line flags
+var renderDefaults = types.DefaultRenderOptions()
+
+// modeFlag forces a render mode instead of picking the best available one
+var modeFlag string
+
+// glyphsFlag selects the glyph set for BLOCKS mode
+var glyphsFlag string
+
+// ditherFlag selects the dithering used by palette-limited renderers
+var ditherFlag string
+
+// rampFlag selects a ramp preset or a custom ramp string for ASCII gray mode
+var rampFlag string
+
+// fitFlag selects how media is scaled to the terminal
+var fitFlag string
+
+// letterboxFlag sets the color around centered frames ("none" keeps the
+// terminal's, "terminal" paints its detected background color)
+var letterboxFlag string
+
+// backgroundFlag selects what transparent pixels are drawn over
+var backgroundFlag string
+
+// invertRampFlag controls ramp inversion for light backgrounds (auto, on, off)
+var invertRampFlag string
+
+// parseFlags reads command-line flags into renderDefaults
+func parseFlags() {
+	flag.StringVar(&modeFlag, "mode", "", "force a render mode (kitty, iterm2, sixel, exact, halfblock_256, braille, blocks, ascii_color, ascii_16, ascii_gray, ascii_shape)")
+	flag.StringVar(&glyphsFlag, "glyphs", "quadrants", "glyph set for blocks mode (quadrants, sextants, octants) - depends on font coverage")
+	flag.StringVar(&ditherFlag, "dither", "none", "dithering for ascii, halfblock_256, braille and sixel (none, floyd-steinberg, atkinson, bayer4, bayer8, blue-noise)")
+	flag.StringVar(&rampFlag, "ramp", "short", "ascii gray ramp: a preset (short, long, shades, blocks) or custom characters from dark to bright")
+	flag.StringVar(&invertRampFlag, "invert-ramp", "auto", "invert the ascii ramp for light-background terminals (auto, on, off)")
+	flag.StringVar(&fitFlag, "fit", "contain", "how media is scaled to the terminal (contain, cover, stretch, original, fit-width)")
+	flag.StringVar(&letterboxFlag, "letterbox", "none", "color around centered frames: none, terminal (the detected background) or a #rrggbb color")
+	flag.StringVar(&backgroundFlag, "background", "terminal", "background for transparent images: terminal, checkerboard, transparent (sixel), or a #rrggbb color")
+	flag.IntVar(&renderDefaults.PaletteSize, "palette-size", renderDefaults.PaletteSize, "maximum SIXEL palette colors per image (2-256)")
+	flag.IntVar(&renderDefaults.BrailleThreshold, "braille-threshold", renderDefaults.BrailleThreshold, "luminance cutoff 1-255 for braille dots (0 = automatic)")
+	flag.BoolVar(&renderDefaults.BrailleColor, "braille-color", renderDefaults.BrailleColor, "color braille cells with their average color")
+	flag.Float64Var(&renderDefaults.Brightness, "brightness", renderDefaults.Brightness, "brightness offset (-1 to 1)")
+	flag.Float64Var(&renderDefaults.Contrast, "contrast", renderDefaults.Contrast, "contrast multiplier (0 to 4)")
+	flag.Float64Var(&renderDefaults.Gamma, "gamma", renderDefaults.Gamma, "gamma correction (0.1 to 5, >1 lifts midtones)")
+	flag.Float64Var(&renderDefaults.Saturation, "saturation", renderDefaults.Saturation, "saturation multiplier (0 = grayscale, up to 4)")
+	flag.Float64Var(&renderDefaults.HueShift, "hue", renderDefaults.HueShift, "hue rotation in degrees")
+	flag.Float64Var(&renderDefaults.Sharpen, "sharpen", renderDefaults.Sharpen, "sharpen strength (0 = off, up to 4)")
+	flag.BoolVar(&renderDefaults.Invert, "invert", renderDefaults.Invert, "invert all colors")
+	flag.Parse()
+
+	renderDefaults.Adjustments = renderDefaults.Adjustments.Clamped()
+
+	glyphs, err := types.ParseGlyphSet(glyphsFlag)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	renderDefaults.BlockGlyphs = glyphs
+
+	dither, err := types.ParseDitherMode(ditherFlag)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	renderDefaults.Dither = dither
+
+	if preset, err := types.ParseRampPreset(rampFlag); err == nil {
+		renderDefaults.Ramp = preset.Characters()
+	} else if utf8.RuneCountInString(rampFlag) >= 2 {
+		renderDefaults.Ramp = rampFlag
+	} else {
+		fmt.Printf("Error: ramp must be a preset or at least 2 characters: %q\n", rampFlag)
+		os.Exit(1)
+	}
+
+	fit, err := types.ParseFitMode(fitFlag)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	renderDefaults.Fit = fit
+
+	switch {
+	case strings.EqualFold(letterboxFlag, "none"):
+	case strings.EqualFold(letterboxFlag, "terminal"):
+		// The color is known once the terminal has been queried
+		renderDefaults.Letterbox = true
+	default:
+		color, err := parseHexColor(letterboxFlag)
+		if err != nil {
+			fmt.Printf("Error: letterbox must be none, terminal or a #rrggbb color: %q\n", letterboxFlag)
+			os.Exit(1)
+		}
+		renderDefaults.Letterbox = true
+		renderDefaults.LetterboxColor = color
+	}
+
+	if background, err := types.ParseBackgroundMode(backgroundFlag); err == nil {
+		renderDefaults.Background = background
+	} else if color, err := parseHexColor(backgroundFlag); err == nil {
+		renderDefaults.Background = types.BACKGROUND_SOLID
+		renderDefaults.BackgroundColor = color
+	} else {
+		fmt.Printf("Error: background must be terminal, checkerboard, transparent or a #rrggbb color: %q\n", backgroundFlag)
+		os.Exit(1)
+	}
+
+	switch strings.ToLower(invertRampFlag) {
+	case "auto", "on", "off":
+	default:
+		fmt.Printf("Error: invalid -invert-ramp value %q (use auto, on or off)\n", invertRampFlag)
+		os.Exit(1)
+	}
+}
+
+// invertRamp reports whether the ASCII ramp is inverted for an -invert-ramp
+// setting: auto inverts it on light backgrounds
+func invertRamp(setting string, lightBackground bool) bool {
+	switch strings.ToLower(setting) {
+	case "on":
+		return true
+	case "auto":
+		return lightBackground
+	default:
+		return false
+	}
+}
+
+// parseHexColor parses a "#rrggbb" (or "rrggbb") color
+func parseHexColor(value string) ([3]uint8, error) {
+	hex := strings.TrimPrefix(value, "#")
+	if len(hex) != 6 {
+		return [3]uint8{}, fmt.Errorf("invalid color: %s", value)
+	}
+
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return [3]uint8{}, fmt.Errorf("invalid color: %s", value)
+	}
+	return [3]uint8{uint8(n >> 16), uint8(n >> 8), uint8(n)}, nil
+}
+
 func main() {
+	parseFlags()
+
 	// Initialize terminal control
 	termControl := terminal.NewControl()
 	defer termControl.ShowCursor()
@@ -114,9 +401,13 @@ func main() {
 
 	go func() {
 		<-signalChan
-		termControl.ShowCursor()
+		close(interrupted)
+
+		// Nothing may be watching for the first signal (a prompt waiting for
+		// Enter, say), so a second one restores the terminal and exits at once
+		<-signalChan
 		termControl.Reset()
-		os.Exit(0)
+		os.Exit(1)
 	}()
 
 	// Detect terminal capabilities
@@ -132,9 +423,54 @@ func main() {
 		}
 	}
 
+	renderDefaults.InvertRamp = invertRamp(invertRampFlag, capabilities.LightBackground)
+
+	// Paint frames in one go where the terminal can hold them back
+	termControl.SetSynchronizedOutput(capabilities.SynchronizedOutput)
+
+	// Transparent pixels fall back to the terminal's background color
+	renderDefaults.TerminalBackground = capabilities.BackgroundColor
+	if strings.EqualFold(letterboxFlag, "terminal") {
+		renderDefaults.LetterboxColor = capabilities.BackgroundColor
+	}
+	renderDefaults.LetterboxSGR = renderer.BackgroundSGR(
+		renderDefaults.LetterboxColor, capabilities.TrueColor, capabilities.Color256)
+
+	// Match the menus to the terminal's theme
+	tui.SetLightBackground(capabilities.LightBackground)
+
+	// Size frames for the terminal's real cell shape when it is known
+	if capabilities.CellWidth > 0 && capabilities.CellHeight > 0 {
+		renderDefaults.CellPixelWidth = capabilities.CellWidth
+		renderDefaults.CellPixelHeight = capabilities.CellHeight
+		renderDefaults.TerminalAspectRatio = float64(capabilities.CellWidth) / float64(capabilities.CellHeight)
+	}
+
+	// Graphics sequences must be passed through tmux or screen to reach the terminal
+	renderDefaults.Passthrough = capabilities.Passthrough
+
+	// Keep SIXEL frames within what the terminal will draw
+	renderDefaults.SixelMaxWidth = capabilities.SixelMaxWidth
+	renderDefaults.SixelMaxHeight = capabilities.SixelMaxHeight
+	if capabilities.SixelColorRegisters > 0 {
+		renderDefaults.PaletteSize = min(renderDefaults.PaletteSize, capabilities.SixelColorRegisters)
+	}
+
 	// Initialize renderer manager
 	rendererManager := renderer.NewRendererManager(capabilities)
 
+	// Apply a forced render mode from the command line
+	if modeFlag != "" {
+		mode, err := types.ParseRenderMode(modeFlag)
+		if err == nil {
+			err = rendererManager.SetPreferredMode(mode)
+		}
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Check for missing dependencies on first run
 	if tui.ShouldShowInstaller() {
 		installerModel := tui.NewInstallerModel()
@@ -188,6 +524,14 @@ func main() {
 			runRenderingTests(rendererManager, capabilities)
 		}
 
+		// An interrupt stopped the action; leave instead of showing the menu
+		select {
+		case <-interrupted:
+			termControl.Reset()
+			return
+		default:
+		}
+
 		// Wait for user input before returning to TUI (except for tests which likely have their own pause)
 		// For consistency, most handlers should pause themselves if needed.
 		// We'll add a generic pause here just in case, but handlers usually clear screen at end.
@@ -201,10 +545,32 @@ func main() {
 // displayTerminalInfo shows detected terminal capabilities
 func displayTerminalInfo(capabilities types.TerminalCapabilities) {
 	fmt.Printf("Terminal: %dx%d\n", capabilities.Width, capabilities.Height)
-	fmt.Printf("SIXEL Support: %v\n", capabilities.SixelSupport)
+	if capabilities.CellWidth > 0 && capabilities.CellHeight > 0 {
+		fmt.Printf("Cell Size: %dx%d pixels (%s)\n", capabilities.CellWidth, capabilities.CellHeight, capabilities.CellSizeMethod)
+	} else {
+		fmt.Println("Cell Size: unknown")
+	}
+	if capabilities.Passthrough != types.MULTIPLEXER_NONE {
+		fmt.Printf("Multiplexer: %s (graphics passed through)\n", capabilities.Multiplexer)
+	} else if capabilities.Multiplexer != types.MULTIPLEXER_NONE {
+		fmt.Printf("Multiplexer: %s (passthrough disabled)\n", capabilities.Multiplexer)
+	}
+	fmt.Printf("SIXEL Support: %v (%s)\n", capabilities.SixelSupport, capabilities.SixelMethod)
+	if capabilities.SixelColorRegisters > 0 {
+		fmt.Printf("SIXEL Color Registers: %d\n", capabilities.SixelColorRegisters)
+	}
+	if capabilities.SixelMaxWidth > 0 && capabilities.SixelMaxHeight > 0 {
+		fmt.Printf("SIXEL Max Size: %dx%d pixels\n", capabilities.SixelMaxWidth, capabilities.SixelMaxHeight)
+	}
+	fmt.Printf("Kitty Graphics: %v (%s)\n", capabilities.KittyGraphics, capabilities.KittyMethod)
+	fmt.Printf("iTerm2 Images: %v (%s)\n", capabilities.ITerm2Images, capabilities.ITerm2Method)
 	fmt.Printf("True Color (24-bit): %v\n", capabilities.TrueColor)
 	fmt.Printf("256 Colors: %v\n", capabilities.Color256)
 	fmt.Printf("Unicode Support: %v\n", capabilities.UnicodeSupport)
+	fmt.Printf("Synchronized Output: %v\n", capabilities.SynchronizedOutput)
+	fmt.Printf("Light Background: %v (%s)\n", capabilities.LightBackground, capabilities.ColorMethod)
+	fg, bg := capabilities.ForegroundColor, capabilities.BackgroundColor
+	fmt.Printf("Colors: foreground #%02x%02x%02x, background #%02x%02x%02x\n", fg[0], fg[1], fg[2], bg[0], bg[1], bg[2])
 
 	// Debug: Show environment variables
 	fmt.Printf("TERM: %s\n", os.Getenv("TERM"))
@@ -219,7 +585,7 @@ func displayTerminalInfo(capabilities types.TerminalCapabilities) {
 // handleImageDisplay handles static image display
 func handleImageDisplay(rendererManager *renderer.RendererManager, termControl *terminal.Control, capabilities types.TerminalCapabilities, imagePath string) {
 	if imagePath == "" {
-		fmt.Print("Enter image file path: ")
+		fmt.Fprint(termControl, "Enter image file path: ")
 		scanner := bufio.NewScanner(os.Stdin)
 		if !scanner.Scan() {
 			return
@@ -228,7 +594,7 @@ func handleImageDisplay(rendererManager *renderer.RendererManager, termControl *
 	}
 
 	if imagePath == "" {
-		fmt.Println("No path provided.")
+		fmt.Fprintln(termControl, "No path provided.")
 		// Wait before returning
 		time.Sleep(1 * time.Second)
 		return
@@ -236,86 +602,90 @@ func handleImageDisplay(rendererManager *renderer.RendererManager, termControl *
 
 	// Check if file exists
 	if _, err := os.Stat(imagePath); os.IsNotExist(err) {
-		fmt.Printf("File not found: %s\n", imagePath)
+		fmt.Fprintf(termControl, "File not found: %s\n", imagePath)
 		return
 	}
 
 	// Decode image
 	imageDecoder := decoder.NewImageDecoder()
 	if !imageDecoder.IsSupported(imagePath) {
-		fmt.Println("Unsupported image format.")
+		fmt.Fprintln(termControl, "Unsupported image format.")
 		return
 	}
 
 	img, mediaInfo, err := imageDecoder.DecodeImage(imagePath)
 	if err != nil {
-		fmt.Printf("Failed to decode image: %v\n", err)
+		fmt.Fprintf(termControl, "Failed to decode image: %v\n", err)
 		return
 	}
 
-	fmt.Printf("Image loaded: %dx%d pixels\n", mediaInfo.Width, mediaInfo.Height)
+	fmt.Fprintf(termControl, "Image loaded: %dx%d pixels\n", mediaInfo.Width, mediaInfo.Height)
+	if metadata := mediaInfo.Metadata; metadata != nil {
+		fmt.Fprintf(termControl, "Camera: %s, Taken: %s, GPS: %v\n", metadata.Camera(), metadata.DateTaken, metadata.HasGPS)
+	}
 
 	// Get best renderer
 	bestRenderer := rendererManager.GetBestRenderer()
-	fmt.Printf("Using renderer: %s\n", bestRenderer.Name())
+	fmt.Fprintf(termControl, "Using renderer: %s\n", bestRenderer.Name())
 
 	// Initialize renderer
 	if err := bestRenderer.Initialize(); err != nil {
-		fmt.Printf("Failed to initialize renderer: %v\n", err)
+		fmt.Fprintf(termControl, "Failed to initialize renderer: %v\n", err)
 		return
 	}
 	defer bestRenderer.Cleanup()
 
 	// Set up render options for full terminal usage
-	options := types.DefaultRenderOptions()
+	options := renderDefaults
+
+	// Select rendering mode matching the renderer in use
+	options.Mode = rendererManager.GetBestMode()
+	fmt.Fprintf(termControl, "Using %s mode\n", options.Mode)
 
-	// Calculate optimal render size using the new function
-	options.Width, options.Height = calculateOptimalRenderSize(
+	// Lay out the image for the fit mode
+	layout := calculateOptimalRenderSize(
 		mediaInfo.Width, mediaInfo.Height,
-		capabilities.Width, capabilities.Height)
+		capabilities.Width, capabilities.Height, options)
+	layout.apply(&options)
 
-	// Debug: Show terminal size calculation
-	fmt.Printf("Terminal size: %dx%d, Available space: %dx%d\n",
-		capabilities.Width, capabilities.Height, capabilities.Width-1, capabilities.Height-3)
+	fmt.Fprintf(termControl, "Terminal size: %dx%d, fit: %s\n",
+		capabilities.Width, capabilities.Height, options.Fit)
 
-	fmt.Printf("Rendering at %dx%d (original: %dx%d) - Utilization: %.1f%%\n",
+	fmt.Fprintf(termControl, "Rendering at %dx%d (original: %dx%d) at row %d, column %d\n",
 		options.Width, options.Height, mediaInfo.Width, mediaInfo.Height,
-		float64(options.Width*options.Height)/float64((capabilities.Width-1)*(capabilities.Height-3))*100)
+		options.OriginRow+1, options.OriginColumn+1)
 
 	// Debug: Show color capabilities
-	fmt.Printf("Color capabilities - True Color: %v, 256 Color: %v, SIXEL: %v\n",
-		capabilities.TrueColor, capabilities.Color256, capabilities.SixelSupport)
-
-	// Select rendering mode based on capabilities (prefer color modes)
-	if capabilities.SixelSupport {
-		options.Mode = types.SIXEL
-		fmt.Println("Using SIXEL graphics mode")
-	} else if capabilities.TrueColor {
-		options.Mode = types.EXACT
-		fmt.Println("Using Unicode true-color mode")
-	} else if capabilities.Color256 {
-		options.Mode = types.ASCII_COLOR
-		fmt.Println("Using ASCII color mode")
-	} else {
-		// Force ASCII color even if detection fails - most terminals support it
-		options.Mode = types.ASCII_COLOR
-		fmt.Println("Using ASCII color mode (forced fallback)")
+	fmt.Fprintf(termControl, "Color capabilities - True Color: %v, 256 Color: %v, SIXEL: %v, Kitty: %v, iTerm2: %v\n",
+		capabilities.TrueColor, capabilities.Color256, capabilities.SixelSupport,
+		capabilities.KittyGraphics, capabilities.ITerm2Images)
+
+	// View interactively when keys can be read
+	if keys, err := termControl.EnableKeyInput(); err == nil {
+		termControl.HideCursor()
+		viewImage(img, imageInfoLines(imagePath, mediaInfo), bestRenderer, termControl, capabilities, options, keys)
+		termControl.DisableKeyInput()
+		termControl.ShowCursor()
+		termControl.ClearScreen()
+		return
 	}
 
-	// Render image
-	fmt.Println("Rendering image...")
-	rendered, err := bestRenderer.Render(img, options)
+	// Otherwise render it once
+	fmt.Fprintln(termControl, "Rendering image...")
+	rendered, err := bestRenderer.Render(layout.crop(img), options)
 	if err != nil {
-		fmt.Printf("Failed to render image: %v\n", err)
+		fmt.Fprintf(termControl, "Failed to render image: %v\n", err)
 		return
 	}
 
 	// Display image
-	termControl.ClearScreen()
+	termControl.BeginFrame()
+	clearScreen(termControl, options)
 	termControl.HideCursor()
-	fmt.Print(rendered)
+	termControl.WriteString(rendered)
+	termControl.EndFrame()
 
-	fmt.Printf("\nPress Enter to continue...")
+	fmt.Fprintf(termControl, "\nPress Enter to continue...")
 	bufio.NewScanner(os.Stdin).Scan() // Use fresh scanner
 
 	termControl.ShowCursor()
@@ -397,106 +767,206 @@ func playGIFFile(gifPath string, rendererManager *renderer.RendererManager, term
 	// Load GIF
 	gifDecoder := decoder.NewGIFDecoder()
 	if !gifDecoder.IsSupported(gifPath) {
-		fmt.Println("Not a valid GIF file.")
+		fmt.Fprintln(termControl, "Not a valid GIF file.")
 		time.Sleep(2 * time.Second)
 		return
 	}
 
 	mediaInfo, err := gifDecoder.LoadGIF(gifPath)
 	if err != nil {
-		fmt.Printf("Failed to load GIF: %v\n", err)
+		fmt.Fprintf(termControl, "Failed to load GIF: %v\n", err)
 		return
 	}
 
-	fmt.Printf("GIF loaded: %dx%d pixels, %d frames, %.1f FPS\n",
+	fmt.Fprintf(termControl, "GIF loaded: %dx%d pixels, %d frames, %.1f FPS\n",
 		mediaInfo.Width, mediaInfo.Height, mediaInfo.FrameCount, mediaInfo.FPS)
 
 	// Get renderer
 	bestRenderer := rendererManager.GetBestRenderer()
-	fmt.Printf("Using renderer: %s\n", bestRenderer.Name())
+	fmt.Fprintf(termControl, "Using renderer: %s\n", bestRenderer.Name())
 
 	if err := bestRenderer.Initialize(); err != nil {
-		fmt.Printf("Failed to initialize renderer: %v\n", err)
+		fmt.Fprintf(termControl, "Failed to initialize renderer: %v\n", err)
 		return
 	}
 	defer bestRenderer.Cleanup()
 
 	// Set up render options with optimal sizing
-	options := types.DefaultRenderOptions()
+	options := renderDefaults
 
-	// Calculate optimal render size
-	options.Width, options.Height = calculateOptimalRenderSize(
-		mediaInfo.Width, mediaInfo.Height,
-		capabilities.Width, capabilities.Height)
+	// Keep the SIXEL palette stable across frames to avoid color flicker
+	options.TemporalPalette = true
 
-	fmt.Printf("GIF render size: %dx%d (original: %dx%d)\n",
-		options.Width, options.Height, mediaInfo.Width, mediaInfo.Height)
+	// Only repaint the cells that changed between frames in text modes
+	options.DeltaFrames = true
 
-	// Select rendering mode
-	if capabilities.SixelSupport {
-		options.Mode = types.SIXEL
-	} else if capabilities.TrueColor {
-		options.Mode = types.EXACT
-	} else if capabilities.Color256 {
-		options.Mode = types.ASCII_COLOR
-	} else {
-		options.Mode = types.ASCII_GRAY
+	// Unset SIXEL pixels would keep showing the previous frame
+	if options.Background == types.BACKGROUND_TRANSPARENT {
+		options.Background = types.BACKGROUND_TERMINAL
 	}
 
-	fmt.Println("Playing GIF... Press Ctrl+C to stop")
+	// Select rendering mode matching the renderer in use
+	options.Mode = rendererManager.GetBestMode()
+
+	// Lay out the GIF for the fit mode
+	layout := calculateOptimalRenderSize(
+		mediaInfo.Width, mediaInfo.Height,
+		capabilities.Width, capabilities.Height, options)
+	layout.apply(&options)
+
+	fmt.Fprintf(termControl, "GIF render size: %dx%d (original: %dx%d)\n",
+		options.Width, options.Height, mediaInfo.Width, mediaInfo.Height)
+
+	fmt.Fprintln(termControl, "Playing GIF...")
+	fmt.Fprintln(termControl, gifKeyHelp)
+	fmt.Fprintln(termControl, adjustmentKeyHelp)
 	time.Sleep(1 * time.Second)
 
 	// Play GIF
-	termControl.ClearScreen()
+	clearScreen(termControl, options)
 	termControl.HideCursor()
 
+	// Read control keys while playing; playback works without them
+	keys, err := termControl.EnableKeyInput()
+	if err == nil {
+		defer termControl.DisableKeyInput()
+	}
+	var overlay statusOverlay
+
 	// Get frame channel
 	frameChan, err := gifDecoder.GetFrameChannel()
 	if err != nil {
-		fmt.Printf("Failed to get frame channel: %v\n", err)
+		fmt.Fprintf(termControl, "Failed to get frame channel: %v\n", err)
 		return
 	}
 
-	// Play frames
-	lastResizeCheck := time.Now()
-	for frame := range frameChan {
-		// Check for terminal resize every second
-		if time.Since(lastResizeCheck) >= time.Second {
-			newCapabilities, err := terminal.DetectCapabilities()
-			if err == nil {
-				newWidth, newHeight := calculateOptimalRenderSize(
-					mediaInfo.Width, mediaInfo.Height,
-					newCapabilities.Width, newCapabilities.Height)
-
-				// Only update if size changed significantly
-				if newWidth != options.Width || newHeight != options.Height {
-					fmt.Printf("\nTerminal resized to %dx%d, adjusting GIF size to %dx%d\n",
-						newCapabilities.Width, newCapabilities.Height, newWidth, newHeight)
-					options.Width = newWidth
-					options.Height = newHeight
-					capabilities = newCapabilities
+	// Repaint around a new layout as soon as the terminal is resized
+	resized, stopResize := terminal.WatchResize()
+	defer stopResize()
+
+	stats := &types.PlaybackStats{
+		StartTime: time.Now().UnixNano(),
+	}
+
+	// Play frames; anything but a new frame shows the current one again
+	var frame *types.Frame
+	var pausedAt time.Time
+	relayout, repaint, repeat := false, false, false
+	paused, showStats := false, false
+gifLoop:
+	for {
+		// Take a status message down in time while no frames arrive
+		var expired <-chan time.Time
+		if paused {
+			expired = overlay.expiry()
+		}
+
+		repeat = true
+		select {
+		case next, ok := <-frameChan:
+			if !ok {
+				break gifLoop
+			}
+			frame, repeat = next, false
+		case <-resized:
+			if !refreshTerminalSize(&capabilities, &options) {
+				continue
+			}
+			relayout = true
+		case key := <-keys:
+			switch {
+			case isStopKey(key):
+				break gifLoop
+			case key.Key == terminal.KeyRune && key.Rune == ' ':
+				paused = !paused
+				if paused {
+					gifDecoder.Pause()
+					pausedAt = time.Now()
+					overlay.setStatus("Paused")
+				} else {
+					gifDecoder.Resume()
+					stats.StartTime += time.Since(pausedAt).Nanoseconds()
+					overlay.setStatus("")
 				}
+			case key.Key == terminal.KeyLeft:
+				gifDecoder.StepFrames(-1)
+				continue
+			case key.Key == terminal.KeyRight:
+				gifDecoder.StepFrames(1)
+				continue
+			case key.Key == terminal.KeyRune && key.Rune == 'f':
+				// Hiding the stats repaints the frame they were drawn over
+				showStats = !showStats
+				repaint = !showStats
+			case key.Key == terminal.KeyRune && key.Rune == '?':
+				overlay.flash(gifKeyHelp)
+			case overlay.handleKey(&options.Adjustments, key):
+			default:
+				continue
 			}
-			lastResizeCheck = time.Now()
+		case <-expired:
+		case <-interrupted:
+			break gifLoop
+		}
+		if frame == nil {
+			continue
+		}
+
+		// Everything up to EndFrame is painted at once, including any repaint
+		// after a resize
+		termControl.BeginFrame()
+
+		if relayout {
+			// Lay out again and clear what the old frame left behind
+			layout = calculateOptimalRenderSize(
+				mediaInfo.Width, mediaInfo.Height, capabilities.Width, capabilities.Height, options)
+			layout.apply(&options)
+			relayout, repaint = false, true
+		}
+		if repaint {
+			clearScreen(termControl, options)
+			resetFrameTracking(bestRenderer)
+			repaint = false
 		}
 
-		rendered, err := bestRenderer.Render(frame.Image, options)
+		rendered, err := bestRenderer.Render(layout.crop(frame.Image), options)
 		if err != nil {
-			fmt.Printf("Failed to render frame: %v\n", err)
+			termControl.EndFrame()
+			fmt.Fprintf(termControl, "Failed to render frame: %v\n", err)
 			break
 		}
 
 		termControl.MoveCursorHome()
-		fmt.Print(rendered)
+		termControl.WriteString(rendered)
+		overlay.draw(termControl, options, capabilities.Height, capabilities.Width)
+
+		if !repeat {
+			stats.FramesRendered++
+			updatePlaybackStats(stats)
+		}
+		if showStats {
+			drawPlaybackStats(termControl, stats, options, capabilities.Height-1, capabilities.Width, "")
+		}
+
+		termControl.EndFrame()
 
 		// The frame timing is handled by the decoder
 	}
 
+	termControl.DisableKeyInput()
 	termControl.ShowCursor()
 	termControl.ClearScreen()
 	gifDecoder.Close()
 }
 
+// resetFrameTracking makes renderers that emit delta frames repaint the
+// whole frame next time, after the screen was cleared underneath them
+func resetFrameTracking(r renderer.Renderer) {
+	if tracker, ok := r.(renderer.FrameTracker); ok {
+		tracker.ResetFrame()
+	}
+}
+
 // handleVideoFromURL handles video playback from URL
 func handleVideoFromURL(rendererManager *renderer.RendererManager, termControl *terminal.Control, capabilities types.TerminalCapabilities, videoURL string) {
 	if videoURL == "" {
@@ -625,99 +1095,77 @@ func playVideoFile(videoPath string, rendererManager *renderer.RendererManager,
 	// Load video
 	videoDecoder := decoder.NewVideoDecoder()
 	if !videoDecoder.IsSupported(videoPath) {
-		fmt.Println("Unsupported video format.")
+		fmt.Fprintln(termControl, "Unsupported video format.")
 		return
 	}
 
 	mediaInfo, err := videoDecoder.LoadVideo(videoPath)
 	if err != nil {
-		fmt.Printf("Failed to load video: %v\n", err)
+		fmt.Fprintf(termControl, "Failed to load video: %v\n", err)
 		return
 	}
 
-	fmt.Printf("Video loaded: %dx%d pixels, %.1f FPS, %.1fs duration\n",
+	fmt.Fprintf(termControl, "Video loaded: %dx%d pixels, %.1f FPS, %.1fs duration\n",
 		mediaInfo.Width, mediaInfo.Height, mediaInfo.FPS, mediaInfo.Duration)
-	fmt.Printf("Video codec: %s, Has audio: %v\n", mediaInfo.VideoCodec, mediaInfo.HasAudio)
+	fmt.Fprintf(termControl, "Video codec: %s, Has audio: %v\n", mediaInfo.VideoCodec, mediaInfo.HasAudio)
 
 	// Initialize audio player if video has audio
 	var audioPlayer *audio.Player
 	if mediaInfo.HasAudio {
 		audioPlayer = audio.NewPlayer()
 		if err := audioPlayer.LoadAudio(videoPath); err != nil {
-			fmt.Printf("Warning: Could not load audio: %v\n", err)
+			fmt.Fprintf(termControl, "Warning: Could not load audio: %v\n", err)
 			audioPlayer = nil
 		} else {
-			fmt.Println("Audio loaded successfully")
+			fmt.Fprintln(termControl, "Audio loaded successfully")
 		}
 	}
 
 	// Get renderer
 	bestRenderer := rendererManager.GetBestRenderer()
-	fmt.Printf("Using renderer: %s\n", bestRenderer.Name())
+	fmt.Fprintf(termControl, "Using renderer: %s\n", bestRenderer.Name())
 
 	if err := bestRenderer.Initialize(); err != nil {
-		fmt.Printf("Failed to initialize renderer: %v\n", err)
+		fmt.Fprintf(termControl, "Failed to initialize renderer: %v\n", err)
 		return
 	}
 	defer bestRenderer.Cleanup()
 
 	// Set up render options with dynamic sizing and adaptive scaling based on FPS
-	options := types.DefaultRenderOptions()
-
-	// Calculate optimal render size based on render mode
-	var pixelWidth, pixelHeight int
-	var optimalWidth, optimalHeight int
-
-	if capabilities.SixelSupport {
-		// SIXEL mode: calculate pixel dimensions directly for full terminal coverage
-		pixelWidth, pixelHeight = calculateOptimalRenderSizeSixel(
-			mediaInfo.Width, mediaInfo.Height,
-			capabilities.Width, capabilities.Height)
+	options := renderDefaults
 
-		// Convert back to character dimensions for options (for renderer info)
-		optimalWidth = pixelWidth / 8
-		optimalHeight = pixelHeight / 16
+	// Keep the SIXEL palette stable across frames to avoid color flicker
+	options.TemporalPalette = true
 
-		fmt.Printf("SIXEL render size: %dx%d pixels (terminal: %dx%d chars)\n",
-			pixelWidth, pixelHeight, capabilities.Width, capabilities.Height)
-	} else {
-		// Unicode/ASCII mode: calculate character dimensions
-		optimalWidth, optimalHeight = calculateOptimalRenderSize(
-			mediaInfo.Width, mediaInfo.Height,
-			capabilities.Width, capabilities.Height)
-
-		// Unicode half-blocks: 2 pixels per character height
-		pixelWidth = optimalWidth
-		pixelHeight = optimalHeight * 2
+	// Only repaint the cells that changed between frames in text modes
+	options.DeltaFrames = true
 
-		fmt.Printf("Optimal render size calculated: %dx%d (terminal: %dx%d)\n",
-			optimalWidth, optimalHeight, capabilities.Width, capabilities.Height)
-	}
+	// Select rendering mode matching the renderer in use
+	options.Mode = rendererManager.GetBestMode()
 
-	options.Width = optimalWidth
-	options.Height = optimalHeight
+	// Lay out the video for the fit mode; the decoder scales frames so the
+	// visible part arrives at the renderer's sampling resolution
+	layout := calculateOptimalRenderSize(
+		mediaInfo.Width, mediaInfo.Height,
+		capabilities.Width, capabilities.Height, options)
+	layout.apply(&options)
+	pixelWidth, pixelHeight := layout.decodeSize(options)
 	videoDecoder.SetRenderSize(pixelWidth, pixelHeight)
 
-	fmt.Printf("Video render size: %dx%d pixels\n", pixelWidth, pixelHeight)
-
-	// Select rendering mode
-	if capabilities.SixelSupport {
-		options.Mode = types.SIXEL
-	} else if capabilities.TrueColor {
-		options.Mode = types.EXACT
-	} else if capabilities.Color256 {
-		options.Mode = types.ASCII_COLOR
-	} else {
-		options.Mode = types.ASCII_GRAY
-	}
+	fmt.Fprintf(termControl, "Optimal render size calculated: %dx%d (terminal: %dx%d, fit: %s)\n",
+		options.Width, options.Height, capabilities.Width, capabilities.Height, options.Fit)
+	fmt.Fprintf(termControl, "Video render size: %dx%d pixels\n", pixelWidth, pixelHeight)
 
-	fmt.Println("Playing video... Press Ctrl+C to stop")
+	fmt.Fprintln(termControl, "Playing video...")
+	fmt.Fprintln(termControl, videoKeyHelp)
+	fmt.Fprintln(termControl, adjustmentKeyHelp)
 	time.Sleep(1 * time.Second)
 
 	// Start audio playback if available
 	if audioPlayer != nil {
 		if err := audioPlayer.Play(); err != nil {
-			fmt.Printf("Warning: Could not start audio: %v\n", err)
+			fmt.Fprintf(termControl, "Warning: Could not start audio: %v\n", err)
+			audioPlayer = nil
 		} else {
 			defer audioPlayer.Close()
 		}
@@ -729,105 +1177,202 @@ func playVideoFile(videoPath string, rendererManager *renderer.RendererManager,
 	}
 
 	// Play video
-	termControl.ClearScreen()
+	clearScreen(termControl, options)
 	termControl.HideCursor()
 
+	// Read control keys while playing; playback works without them
+	keys, err := termControl.EnableKeyInput()
+	if err == nil {
+		defer termControl.DisableKeyInput()
+	}
+	var overlay statusOverlay
+
 	// Get frame channel
 	frameChan, err := videoDecoder.GetFrameChannel()
 	if err != nil {
-		fmt.Printf("Failed to get frame channel: %v\n", err)
+		fmt.Fprintf(termControl, "Failed to get frame channel: %v\n", err)
 		return
 	}
 
-	// Frame timing variables
-	_ = time.Duration(1000000000.0 / mediaInfo.FPS) // Frame duration handled by decoder
-	lastResizeCheck := time.Now()
-	resizeCheckInterval := 1 * time.Second // Check for resize every second
-
-	// Play frames with timing and dynamic resizing
-	for frame := range frameChan {
-		currentTime := time.Now()
-
-		// Check for terminal resize periodically
-		if currentTime.Sub(lastResizeCheck) > resizeCheckInterval {
-			if newWidth, newHeight, err := getCurrentTerminalSize(); err == nil {
-				if newWidth != capabilities.Width || newHeight != capabilities.Height {
-					// Terminal size changed - recalculate render size
-					capabilities.Width = newWidth
-					capabilities.Height = newHeight
-
-					// Recalculate optimal size with performance scaling
-					newRenderWidth, newRenderHeight := calculateOptimalRenderSize(
-						mediaInfo.Width, mediaInfo.Height, newWidth, newHeight)
-
-					// Apply performance scaling
-					scaleFactor := 1.0
-					if mediaInfo.FPS > 50 {
-						scaleFactor = 0.85
-					} else if mediaInfo.FPS > 30 {
-						scaleFactor = 0.90
-					}
+	// Repaint around a new layout as soon as the terminal is resized
+	resized, stopResize := terminal.WatchResize()
+	defer stopResize()
+
+	// Play frames with timing and dynamic resizing; anything but a new frame
+	// shows the current one again
+	var frame *types.Frame
+	var pausedAt time.Time
+	relayout, repaint, repeat := false, false, false
+	paused, showStats := false, true
+videoLoop:
+	for {
+		// Take a status message down in time while no frames arrive
+		var expired <-chan time.Time
+		if paused {
+			expired = overlay.expiry()
+		}
 
-					options.Width = int(float64(newRenderWidth) * scaleFactor)
-					options.Height = int(float64(newRenderHeight) * scaleFactor)
+		repeat = true
+		select {
+		case next, ok := <-frameChan:
+			if !ok {
+				break videoLoop
+			}
+			frame, repeat = next, false
+		case <-resized:
+			if !refreshTerminalSize(&capabilities, &options) {
+				continue
+			}
+			relayout = true
+		case key := <-keys:
+			switch {
+			case isStopKey(key):
+				break videoLoop
+			case key.Key == terminal.KeyRune && key.Rune == ' ':
+				paused = !paused
+				if paused {
+					videoDecoder.Pause()
+					if audioPlayer != nil {
+						audioPlayer.Pause()
+					}
+					pausedAt = time.Now()
+					overlay.setStatus("Paused")
+				} else {
+					videoDecoder.Resume()
+					if audioPlayer != nil {
+						audioPlayer.Resume()
+					}
+					stats.StartTime += time.Since(pausedAt).Nanoseconds()
+					overlay.setStatus("")
+				}
+			case key.Key == terminal.KeyLeft || key.Key == terminal.KeyRight:
+				position := 0.0
+				if frame != nil {
+					position = frame.Timestamp
+				}
+				if key.Key == terminal.KeyLeft {
+					position -= seekStep
+				} else {
+					position += seekStep
+				}
+				position = min(max(position, 0), mediaInfo.Duration)
 
-					// Clear screen and update display
-					termControl.ClearScreen()
-					fmt.Printf("Terminal resized to %dx%d, adjusting render size to %dx%d\n",
-						newWidth, newHeight, options.Width, options.Height)
+				videoDecoder.Seek(position)
+				if audioPlayer != nil {
+					audioPlayer.Seek(time.Duration(position * float64(time.Second)))
+				}
+				overlay.flash(fmt.Sprintf("Seek %s / %s",
+					formatPlaybackTime(position), formatPlaybackTime(mediaInfo.Duration)))
+			case key.Key == terminal.KeyUp || key.Key == terminal.KeyDown:
+				if audioPlayer == nil {
+					overlay.flash("No audio")
+					break
+				}
+				volume := audioPlayer.GetVolume()
+				if key.Key == terminal.KeyUp {
+					volume += volumeStep
+				} else {
+					volume -= volumeStep
 				}
+				// Round off the float steps so the ends are reached exactly
+				volume = min(max(math.Round(volume*100)/100, 0), 1)
+				audioPlayer.SetVolume(volume)
+				overlay.flash(fmt.Sprintf("Volume %.0f%%", volume*100))
+			case key.Key == terminal.KeyRune && key.Rune == 'm':
+				if audioPlayer == nil {
+					overlay.flash("No audio")
+					break
+				}
+				muted := !audioPlayer.IsMuted()
+				audioPlayer.SetMuted(muted)
+				if muted {
+					overlay.flash("Muted")
+				} else {
+					overlay.flash(fmt.Sprintf("Volume %.0f%%", audioPlayer.GetVolume()*100))
+				}
+			case key.Key == terminal.KeyRune && key.Rune == 'f':
+				// Hiding the stats repaints the frame they were drawn over
+				showStats = !showStats
+				repaint = !showStats
+			case key.Key == terminal.KeyRune && key.Rune == '?':
+				overlay.flash(videoKeyHelp)
+			case overlay.handleKey(&options.Adjustments, key):
+			default:
+				continue
 			}
-			lastResizeCheck = currentTime
+		case <-expired:
+		case <-interrupted:
+			break videoLoop
+		}
+		if frame == nil {
+			continue
+		}
+
+		// Everything up to EndFrame is painted at once, including any repaint
+		// after a resize
+		termControl.BeginFrame()
+
+		if relayout {
+			// Lay out again, have ffmpeg scale to the new size from where
+			// playback is, and clear what the old frame left behind. Frames
+			// decoded at the old size are cropped and scaled by the renderer
+			// until the new ones arrive.
+			layout = calculateOptimalRenderSize(
+				mediaInfo.Width, mediaInfo.Height, capabilities.Width, capabilities.Height, options)
+			layout.apply(&options)
+			videoDecoder.Rescale(layout.decodeSize(options))
+			relayout, repaint = false, true
+		}
+		if repaint {
+			clearScreen(termControl, options)
+			resetFrameTracking(bestRenderer)
+			repaint = false
 		}
 
 		// Render frame
-		rendered, err := bestRenderer.Render(frame.Image, options)
+		rendered, err := bestRenderer.Render(layout.crop(frame.Image), options)
 		if err != nil {
-			fmt.Printf("Failed to render frame: %v\n", err)
+			termControl.EndFrame()
+			fmt.Fprintf(termControl, "Failed to render frame: %v\n", err)
 			break
 		}
 
 		// Display frame - move cursor to home position
 		// For SIXEL, new image overwrites old at same position (no clear needed)
-		fmt.Print("\033[H") // Move cursor to home (1,1)
-		fmt.Print(rendered)
-
-		stats.FramesRendered++
-
-		// Calculate statistics (but don't display during SIXEL to avoid cursor issues)
-		if stats.FramesRendered%30 == 0 {
-			elapsed := float64(time.Now().UnixNano()-stats.StartTime) / 1000000000.0
-			stats.FPS = float64(stats.FramesRendered) / elapsed
-			if stats.FramesRendered+stats.FramesDropped > 0 {
-				stats.DropRate = float64(stats.FramesDropped) / float64(stats.FramesRendered+stats.FramesDropped) * 100.0
-			}
+		termControl.MoveCursorHome()
+		termControl.WriteString(rendered)
+		overlay.draw(termControl, options, capabilities.Height, capabilities.Width)
 
-			// Only show stats for non-SIXEL modes (SIXEL cursor positioning is tricky)
-			if options.Mode != types.SIXEL {
-				termControl.MoveCursor(capabilities.Height-1, 1)
-				fmt.Printf("FPS: %.1f | Frames: %d | Dropped: %d (%.1f%%) | Size: %dx%d",
-					stats.FPS, stats.FramesRendered, stats.FramesDropped, stats.DropRate, options.Width, options.Height)
-			}
+		if !repeat {
+			stats.FramesRendered++
+			updatePlaybackStats(stats)
 		}
+		if showStats {
+			drawPlaybackStats(termControl, stats, options, capabilities.Height-1, capabilities.Width,
+				formatPlaybackTime(frame.Timestamp)+" / "+formatPlaybackTime(mediaInfo.Duration))
+		}
+
+		termControl.EndFrame()
 	}
 
+	termControl.DisableKeyInput()
 	termControl.ShowCursor()
 	termControl.ClearScreen()
 	videoDecoder.Close()
 
-	// Display final statistics
-	elapsed := float64(time.Now().UnixNano()-stats.StartTime) / 1000000000.0
-	stats.FPS = float64(stats.FramesRendered) / elapsed
-	if stats.FramesRendered+stats.FramesDropped > 0 {
-		stats.DropRate = float64(stats.FramesDropped) / float64(stats.FramesRendered+stats.FramesDropped) * 100.0
+	// Display final statistics, leaving out time spent paused
+	if paused {
+		stats.StartTime += time.Since(pausedAt).Nanoseconds()
 	}
-
-	fmt.Println("\nPlayback Statistics:")
-	fmt.Printf("Total Time: %.1f seconds\n", elapsed)
-	fmt.Printf("Frames Rendered: %d\n", stats.FramesRendered)
-	fmt.Printf("Frames Dropped: %d\n", stats.FramesDropped)
-	fmt.Printf("Drop Rate: %.1f%%\n", stats.DropRate)
-	fmt.Printf("Average FPS: %.1f\n", stats.FPS)
+	elapsed := float64(time.Now().UnixNano()-stats.StartTime) / 1000000000.0
+	updatePlaybackStats(stats)
+
+	fmt.Fprintln(termControl, "\nPlayback Statistics:")
+	fmt.Fprintf(termControl, "Total Time: %.1f seconds\n", elapsed)
+	fmt.Fprintf(termControl, "Frames Rendered: %d\n", stats.FramesRendered)
+	fmt.Fprintf(termControl, "Frames Dropped: %d\n", stats.FramesDropped)
+	fmt.Fprintf(termControl, "Drop Rate: %.1f%%\n", stats.DropRate)
+	fmt.Fprintf(termControl, "Average FPS: %.1f\n", stats.FPS)
 }
 
 // showDetailedTerminalInfo displays detailed terminal information
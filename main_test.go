@@ -0,0 +1,170 @@
+package main
+
+import (
+	"image"
+	"math"
+	"terminaltube/pkg/types"
+	"testing"
+)
+
+func TestInvertRamp(t *testing.T) {
+	tests := []struct {
+		setting         string
+		lightBackground bool
+		want            bool
+	}{
+		{"auto", true, true},
+		{"auto", false, false},
+		{"AUTO", true, true},
+		{"on", false, true},
+		{"on", true, true},
+		{"off", true, false},
+		{"off", false, false},
+	}
+
+	for _, tt := range tests {
+		if got := invertRamp(tt.setting, tt.lightBackground); got != tt.want {
+			t.Errorf("invertRamp(%q, light %v) = %v, want %v", tt.setting, tt.lightBackground, got, tt.want)
+		}
+	}
+}
+
+func TestFrameLayout(t *testing.T) {
+	// An 80x26 terminal leaves an 80x24 frame area; cells are twice as tall
+	// as wide
+	tests := []struct {
+		name                                string
+		imgWidth, imgHeight                 int
+		fit                                 types.FitMode
+		wantWidth, wantHeight               int
+		wantColumn, wantRow                 int
+		cropX, cropY, cropWidth, cropHeight float64
+	}{
+		{"wide contain", 160, 48, types.FIT_CONTAIN, 80, 12, 0, 6, 0, 0, 1, 1},
+		{"wide cover", 160, 48, types.FIT_COVER, 80, 24, 0, 0, 0.25, 0, 0.5, 1},
+		{"wide stretch", 160, 48, types.FIT_STRETCH, 80, 24, 0, 0, 0, 0, 1, 1},
+		{"wide original", 160, 48, types.FIT_ORIGINAL, 80, 24, 0, 0, 0.25, 0, 0.5, 1},
+		{"wide fit-width", 160, 48, types.FIT_WIDTH, 80, 12, 0, 6, 0, 0, 1, 1},
+		{"tall contain", 100, 200, types.FIT_CONTAIN, 24, 24, 28, 0, 0, 0, 1, 1},
+		{"tall cover", 100, 200, types.FIT_COVER, 80, 24, 0, 0, 0, 0.35, 1, 0.3},
+		{"tall stretch", 100, 200, types.FIT_STRETCH, 80, 24, 0, 0, 0, 0, 1, 1},
+		{"tall original", 100, 200, types.FIT_ORIGINAL, 80, 24, 0, 0, 0.1, 0.38, 0.8, 0.24},
+		{"tall fit-width", 100, 200, types.FIT_WIDTH, 80, 24, 0, 0, 0, 0.35, 1, 0.3},
+		{"small original is centered", 20, 10, types.FIT_ORIGINAL, 20, 5, 30, 9, 0, 0, 1, 1},
+		{"small contain is enlarged", 20, 10, types.FIT_CONTAIN, 80, 20, 0, 2, 0, 0, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := types.RenderOptions{Mode: types.EXACT, Fit: tt.fit}
+			layout := calculateOptimalRenderSize(tt.imgWidth, tt.imgHeight, 80, 26, options)
+
+			if layout.width != tt.wantWidth || layout.height != tt.wantHeight ||
+				layout.column != tt.wantColumn || layout.row != tt.wantRow {
+				t.Errorf("frame = %dx%d at column %d, row %d; want %dx%d at column %d, row %d",
+					layout.width, layout.height, layout.column, layout.row,
+					tt.wantWidth, tt.wantHeight, tt.wantColumn, tt.wantRow)
+			}
+			crop := []float64{layout.cropX, layout.cropY, layout.cropWidth, layout.cropHeight}
+			want := []float64{tt.cropX, tt.cropY, tt.cropWidth, tt.cropHeight}
+			for i := range crop {
+				if math.Abs(crop[i]-want[i]) > 1e-9 {
+					t.Errorf("crop = %v, want %v", crop, want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestPlaceFrameKeepsCropInside(t *testing.T) {
+	options := types.RenderOptions{Mode: types.EXACT}
+
+	tests := []struct {
+		name             string
+		centerX, centerY float64
+		cropX, cropY     float64
+	}{
+		{"centered", 0.5, 0.5, 0.25, 0.25},
+		{"past the top left", -1, 0, 0, 0},
+		{"past the bottom right", 1, 2, 0.5, 0.5},
+		{"off center", 0.4, 0.7, 0.15, 0.45},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A picture twice the frame area each way
+			layout := placeFrame(160, 48, tt.centerX, tt.centerY, 80, 26, options)
+			if math.Abs(layout.cropX-tt.cropX) > 1e-9 || math.Abs(layout.cropY-tt.cropY) > 1e-9 {
+				t.Errorf("crop origin = %v, %v; want %v, %v", layout.cropX, layout.cropY, tt.cropX, tt.cropY)
+			}
+		})
+	}
+}
+
+func TestFrameLayoutCrop(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 200, 100))
+	offset := image.NewRGBA(image.Rect(10, 20, 210, 120))
+
+	tests := []struct {
+		name   string
+		img    image.Image
+		layout frameLayout
+		want   image.Rectangle
+	}{
+		{"uncropped", img, frameLayout{cropWidth: 1, cropHeight: 1}, image.Rect(0, 0, 200, 100)},
+		{"center half", img, frameLayout{cropX: 0.25, cropY: 0.25, cropWidth: 0.5, cropHeight: 0.5}, image.Rect(50, 25, 150, 75)},
+		{"right edge", img, frameLayout{cropX: 0.8, cropWidth: 0.2, cropHeight: 1}, image.Rect(160, 0, 200, 100)},
+		{"offset bounds", offset, frameLayout{cropX: 0.5, cropWidth: 0.5, cropHeight: 1}, image.Rect(110, 20, 210, 120)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.layout.crop(tt.img).Bounds(); got != tt.want {
+				t.Errorf("crop bounds = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateTerminalSize(t *testing.T) {
+	tests := []struct {
+		name                  string
+		width, height         int
+		cellWidth, cellHeight int
+		wantChanged           bool
+		wantGrid              [2]int
+		wantCell              [2]int
+	}{
+		{"nothing changed", 80, 24, 10, 20, false, [2]int{80, 24}, [2]int{10, 20}},
+		{"grid grew", 120, 40, 10, 20, true, [2]int{120, 40}, [2]int{10, 20}},
+		{"one dimension changed", 80, 25, 10, 20, true, [2]int{80, 25}, [2]int{10, 20}},
+		{"font size changed", 80, 24, 12, 24, true, [2]int{80, 24}, [2]int{12, 24}},
+		{"unknown cell size", 80, 24, 0, 0, false, [2]int{80, 24}, [2]int{10, 20}},
+		{"unknown grid size", 0, 0, 10, 20, false, [2]int{80, 24}, [2]int{10, 20}},
+		{"unknown grid, new cells", 0, 0, 8, 16, true, [2]int{80, 24}, [2]int{8, 16}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capabilities := types.TerminalCapabilities{Width: 80, Height: 24, CellWidth: 10, CellHeight: 20}
+			options := types.RenderOptions{CellPixelWidth: 10, CellPixelHeight: 20, TerminalAspectRatio: 0.5}
+
+			changed := updateTerminalSize(&capabilities, &options, tt.width, tt.height, tt.cellWidth, tt.cellHeight)
+			if changed != tt.wantChanged {
+				t.Errorf("changed = %v, want %v", changed, tt.wantChanged)
+			}
+			if grid := [2]int{capabilities.Width, capabilities.Height}; grid != tt.wantGrid {
+				t.Errorf("grid = %v, want %v", grid, tt.wantGrid)
+			}
+			cell := [2]int{options.CellPixelWidth, options.CellPixelHeight}
+			if cell != tt.wantCell || [2]int{capabilities.CellWidth, capabilities.CellHeight} != tt.wantCell {
+				t.Errorf("cell = %v (capabilities %dx%d), want %v",
+					cell, capabilities.CellWidth, capabilities.CellHeight, tt.wantCell)
+			}
+			if want := float64(tt.wantCell[0]) / float64(tt.wantCell[1]); options.TerminalAspectRatio != want {
+				t.Errorf("TerminalAspectRatio = %v, want %v", options.TerminalAspectRatio, want)
+			}
+		})
+	}
+}
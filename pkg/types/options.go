@@ -1,6 +1,11 @@
 package types
 
-import "image"
+import (
+	"fmt"
+	"image"
+	"math"
+	"strings"
+)
 
 // RenderMode defines the different rendering modes available
 type RenderMode int
@@ -14,6 +19,20 @@ const (
 	ASCII_GRAY
 	// EXACT uses true-color terminal rendering with Unicode blocks
 	EXACT
+	// KITTY uses the Kitty terminal graphics protocol (kitty, WezTerm, Ghostty)
+	KITTY
+	// ITERM2 uses the iTerm2 inline image protocol (OSC 1337)
+	ITERM2
+	// BRAILLE uses Unicode braille patterns for 2x4 dots per character cell
+	BRAILLE
+	// BLOCKS uses quadrant/sextant/octant block glyphs with best-fit two-color cells
+	BLOCKS
+	// HALFBLOCK_256 uses Unicode half-blocks with the 256-color palette
+	HALFBLOCK_256
+	// ASCII_16 uses block-based color rendering with the 16 basic ANSI colors
+	ASCII_16
+	// ASCII_SHAPE picks the ASCII character whose shape best matches each cell
+	ASCII_SHAPE
 )
 
 // String returns the string representation of the render mode
@@ -27,11 +46,318 @@ func (r RenderMode) String() string {
 		return "ASCII_GRAY"
 	case EXACT:
 		return "EXACT"
+	case KITTY:
+		return "KITTY"
+	case ITERM2:
+		return "ITERM2"
+	case BRAILLE:
+		return "BRAILLE"
+	case BLOCKS:
+		return "BLOCKS"
+	case HALFBLOCK_256:
+		return "HALFBLOCK_256"
+	case ASCII_16:
+		return "ASCII_16"
+	case ASCII_SHAPE:
+		return "ASCII_SHAPE"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ParseRenderMode converts a mode name (case-insensitive) to a RenderMode
+func ParseRenderMode(name string) (RenderMode, error) {
+	for mode := SIXEL; mode.String() != "UNKNOWN"; mode++ {
+		if strings.EqualFold(name, mode.String()) {
+			return mode, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown render mode: %s", name)
+}
+
+// GlyphSet selects the block characters used by the BLOCKS render mode
+type GlyphSet int
+
+const (
+	// QUADRANTS uses 2x2 quadrant blocks (U+2596-U+259F), supported by nearly every font
+	QUADRANTS GlyphSet = iota
+	// SEXTANTS uses 2x3 sextant blocks from Unicode 13 (U+1FB00-U+1FB3B)
+	SEXTANTS
+	// OCTANTS uses 2x4 octant blocks from Unicode 16 (U+1CD00-U+1CDE5)
+	OCTANTS
+)
+
+// String returns the string representation of the glyph set
+func (g GlyphSet) String() string {
+	switch g {
+	case QUADRANTS:
+		return "QUADRANTS"
+	case SEXTANTS:
+		return "SEXTANTS"
+	case OCTANTS:
+		return "OCTANTS"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// CellSize returns how many pixels (columns, rows) one character cell covers
+func (g GlyphSet) CellSize() (int, int) {
+	switch g {
+	case SEXTANTS:
+		return 2, 3
+	case OCTANTS:
+		return 2, 4
+	default:
+		return 2, 2
+	}
+}
+
+// ParseGlyphSet converts a glyph set name (case-insensitive, singular or plural) to a GlyphSet
+func ParseGlyphSet(name string) (GlyphSet, error) {
+	for set := QUADRANTS; set.String() != "UNKNOWN"; set++ {
+		if strings.EqualFold(name, set.String()) || strings.EqualFold(name+"S", set.String()) {
+			return set, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown glyph set: %s", name)
+}
+
+// RampPreset names a built-in character ramp for grayscale ASCII rendering
+type RampPreset int
+
+const (
+	// RAMP_SHORT is the classic 10-character ramp
+	RAMP_SHORT RampPreset = iota
+	// RAMP_LONG is Paul Bourke's 70-character ramp for finer gradations
+	RAMP_LONG
+	// RAMP_SHADES uses the Unicode light/medium/dark shade characters
+	RAMP_SHADES
+	// RAMP_BLOCKS uses the Unicode lower eighth block elements
+	RAMP_BLOCKS
+)
+
+// String returns the string representation of the ramp preset
+func (p RampPreset) String() string {
+	switch p {
+	case RAMP_SHORT:
+		return "SHORT"
+	case RAMP_LONG:
+		return "LONG"
+	case RAMP_SHADES:
+		return "SHADES"
+	case RAMP_BLOCKS:
+		return "BLOCKS"
 	default:
 		return "UNKNOWN"
 	}
 }
 
+// Characters returns the ramp's characters ordered from darkest to brightest
+func (p RampPreset) Characters() string {
+	switch p {
+	case RAMP_LONG:
+		return " .'`^\",:;Il!i><~+_-?][}{1)(|\\/tfjrxnuvczXYUJCLQ0OZmwqpdbkhao*#MW&8%B@$"
+	case RAMP_SHADES:
+		return " ░▒▓█"
+	case RAMP_BLOCKS:
+		return " ▁▂▃▄▅▆▇█"
+	default:
+		return " .:-=+*#%@"
+	}
+}
+
+// ParseRampPreset converts a ramp preset name (case-insensitive) to a RampPreset
+func ParseRampPreset(name string) (RampPreset, error) {
+	for preset := RAMP_SHORT; preset.String() != "UNKNOWN"; preset++ {
+		if strings.EqualFold(name, preset.String()) {
+			return preset, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown ramp preset: %s", name)
+}
+
+// DitherMode selects how palette-limited renderers spread quantization error
+type DitherMode int
+
+const (
+	// DITHER_NONE snaps every pixel to its nearest color independently
+	DITHER_NONE DitherMode = iota
+	// DITHER_FLOYD_STEINBERG diffuses the full error to four neighbours
+	DITHER_FLOYD_STEINBERG
+	// DITHER_ATKINSON diffuses 3/4 of the error, keeping more contrast
+	DITHER_ATKINSON
+	// DITHER_BAYER4 uses a 4x4 ordered threshold matrix (stable across video frames)
+	DITHER_BAYER4
+	// DITHER_BAYER8 uses an 8x8 ordered threshold matrix (stable across video frames)
+	DITHER_BAYER8
+	// DITHER_BLUE_NOISE uses a blue-noise threshold map (stable, less patterned)
+	DITHER_BLUE_NOISE
+)
+
+// String returns the string representation of the dither mode
+func (d DitherMode) String() string {
+	switch d {
+	case DITHER_NONE:
+		return "NONE"
+	case DITHER_FLOYD_STEINBERG:
+		return "FLOYD_STEINBERG"
+	case DITHER_ATKINSON:
+		return "ATKINSON"
+	case DITHER_BAYER4:
+		return "BAYER4"
+	case DITHER_BAYER8:
+		return "BAYER8"
+	case DITHER_BLUE_NOISE:
+		return "BLUE_NOISE"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ParseDitherMode converts a dither mode name (case-insensitive, '-' or '_') to a DitherMode
+func ParseDitherMode(name string) (DitherMode, error) {
+	normalized := strings.ReplaceAll(name, "-", "_")
+	for mode := DITHER_NONE; mode.String() != "UNKNOWN"; mode++ {
+		if strings.EqualFold(normalized, mode.String()) {
+			return mode, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown dither mode: %s", name)
+}
+
+// BackgroundMode selects what transparent pixels are composited onto
+type BackgroundMode int
+
+const (
+	// BACKGROUND_TERMINAL uses the terminal's own background (graphics
+	// protocols with real alpha let the terminal blend it directly)
+	BACKGROUND_TERMINAL BackgroundMode = iota
+	// BACKGROUND_SOLID uses RenderOptions.BackgroundColor
+	BACKGROUND_SOLID
+	// BACKGROUND_CHECKERBOARD uses a gray checkerboard, as image editors do
+	BACKGROUND_CHECKERBOARD
+	// BACKGROUND_TRANSPARENT leaves transparent SIXEL pixels unset so the
+	// terminal shows through; other modes treat it as BACKGROUND_TERMINAL
+	BACKGROUND_TRANSPARENT
+)
+
+// String returns the string representation of the background mode
+func (b BackgroundMode) String() string {
+	switch b {
+	case BACKGROUND_TERMINAL:
+		return "TERMINAL"
+	case BACKGROUND_SOLID:
+		return "SOLID"
+	case BACKGROUND_CHECKERBOARD:
+		return "CHECKERBOARD"
+	case BACKGROUND_TRANSPARENT:
+		return "TRANSPARENT"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ParseBackgroundMode converts a background mode name (case-insensitive) to a BackgroundMode
+func ParseBackgroundMode(name string) (BackgroundMode, error) {
+	for mode := BACKGROUND_TERMINAL; mode.String() != "UNKNOWN"; mode++ {
+		if strings.EqualFold(name, mode.String()) {
+			return mode, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown background mode: %s", name)
+}
+
+// FitMode selects how media is scaled to the terminal
+type FitMode int
+
+const (
+	// FIT_CONTAIN shows the whole picture as large as possible, letterboxing the rest
+	FIT_CONTAIN FitMode = iota
+	// FIT_COVER fills the screen, cropping whatever overflows
+	FIT_COVER
+	// FIT_STRETCH fills the screen, ignoring the aspect ratio
+	FIT_STRETCH
+	// FIT_ORIGINAL shows source pixels 1:1 across, cropping whatever overflows
+	FIT_ORIGINAL
+	// FIT_WIDTH fills the screen width, cropping rows that overflow
+	FIT_WIDTH
+)
+
+// String returns the string representation of the fit mode
+func (f FitMode) String() string {
+	switch f {
+	case FIT_CONTAIN:
+		return "CONTAIN"
+	case FIT_COVER:
+		return "COVER"
+	case FIT_STRETCH:
+		return "STRETCH"
+	case FIT_ORIGINAL:
+		return "ORIGINAL"
+	case FIT_WIDTH:
+		return "WIDTH"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ParseFitMode converts a fit mode name (case-insensitive, '-' or '_', with
+// an optional "fit-" prefix as in fit-width) to a FitMode
+func ParseFitMode(name string) (FitMode, error) {
+	normalized := strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+	normalized = strings.TrimPrefix(normalized, "FIT_")
+	for mode := FIT_CONTAIN; mode.String() != "UNKNOWN"; mode++ {
+		if strings.EqualFold(normalized, mode.String()) {
+			return mode, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown fit mode: %s", name)
+}
+
+// Adjustments are color and detail corrections applied to every frame
+type Adjustments struct {
+	Brightness float64 // Brightness adjustment (0.0 = no change, -1 to 1)
+	Contrast   float64 // Contrast adjustment (1.0 = no change)
+	Gamma      float64 // Gamma correction (1.0 = no change, >1 lifts midtones)
+	Saturation float64 // Saturation multiplier (1.0 = no change, 0 = grayscale)
+	HueShift   float64 // Hue rotation in degrees (0 = no change)
+	Sharpen    float64 // Unsharp-mask strength (0 = off)
+	Invert     bool    // Invert all colors
+}
+
+// DefaultAdjustments returns adjustments that leave images unchanged
+func DefaultAdjustments() Adjustments {
+	return Adjustments{
+		Brightness: 0.0,
+		Contrast:   1.0,
+		Gamma:      1.0,
+		Saturation: 1.0,
+		HueShift:   0.0,
+		Sharpen:    0.0,
+		Invert:     false,
+	}
+}
+
+// IsNeutral reports whether the adjustments leave images unchanged
+func (a Adjustments) IsNeutral() bool {
+	return a == DefaultAdjustments()
+}
+
+// Clamped returns the adjustments limited to their supported ranges
+func (a Adjustments) Clamped() Adjustments {
+	a.Brightness = math.Max(-1, math.Min(1, a.Brightness))
+	a.Contrast = math.Max(0, math.Min(4, a.Contrast))
+	a.Gamma = math.Max(0.1, math.Min(5, a.Gamma))
+	a.Saturation = math.Max(0, math.Min(4, a.Saturation))
+	a.HueShift = math.Mod(a.HueShift, 360)
+	if a.HueShift < 0 {
+		a.HueShift += 360
+	}
+	a.Sharpen = math.Max(0, math.Min(4, a.Sharpen))
+	return a
+}
+
 // RenderOptions contains configuration for media rendering
 type RenderOptions struct {
 	// Width and Height of the output (0 = auto-detect from terminal)
@@ -41,18 +367,79 @@ type RenderOptions struct {
 	// Mode specifies the rendering mode to use
 	Mode RenderMode
 
+	// Fit selects how media is scaled to the terminal; the caller applies it
+	// when choosing Width, Height, the origin and any crop
+	Fit FitMode
+
+	// OriginRow and OriginColumn are the 0-based cell position of the frame's
+	// top-left corner, for centered frames
+	// At the origin (0, 0) renderers write from the current cursor position.
+	OriginRow    int
+	OriginColumn int
+
+	// Letterbox fills the screen around the frame with LetterboxColor
+	// instead of the terminal's background
+	Letterbox      bool
+	LetterboxColor [3]uint8
+	LetterboxSGR   string // SGR parameters selecting LetterboxColor at the terminal's color depth
+
 	// PaletteSize for SIXEL rendering (default 256)
 	PaletteSize int
 
+	// TemporalPalette keeps the previous frame's SIXEL palette while it still
+	// fits, so video and GIF colors don't flicker between frames
+	TemporalPalette bool
+
+	// DeltaFrames makes text renderers emit only the cells that changed since
+	// their previous frame, each run prefixed with a cursor-positioning escape
+	DeltaFrames bool
+
+	// Dither selects how palette-limited renderers (ASCII, 256-color
+	// half-blocks, SIXEL, braille) distribute quantization error
+	Dither DitherMode
+
 	// AspectRatio preservation flag
 	PreserveAspectRatio bool
 
-	// Image adjustments
-	Contrast   float64 // Contrast adjustment (1.0 = no change)
-	Brightness float64 // Brightness adjustment (0.0 = no change)
+	// Image adjustments, applied by every renderer before drawing
+	Adjustments
 
 	// TerminalAspectRatio accounts for character cell dimensions (default 0.5)
 	TerminalAspectRatio float64
+
+	// Pixel size of one character cell as reported by the terminal, 0 = unknown
+	CellPixelWidth  int
+	CellPixelHeight int
+
+	// Largest SIXEL image the terminal draws, 0 = unlimited
+	SixelMaxWidth  int
+	SixelMaxHeight int
+
+	// Passthrough wraps graphics sequences for the multiplexer in between
+	Passthrough Multiplexer
+
+	// Background selects what transparent pixels are composited onto
+	Background      BackgroundMode
+	BackgroundColor [3]uint8 // Color for BACKGROUND_SOLID
+
+	// TerminalBackground is the terminal's background color, used by
+	// BACKGROUND_TERMINAL in modes that cannot leave pixels transparent
+	TerminalBackground [3]uint8
+
+	// Ramp lists the grayscale ASCII characters from darkest to brightest
+	// (a RampPreset's Characters or any custom string of 2+ characters)
+	Ramp string
+
+	// InvertRamp maps bright pixels to sparse characters, for terminals with
+	// a light background where ink is dark
+	InvertRamp bool
+
+	// Braille rendering
+	BrailleThreshold int  // Luminance cutoff (1-255) for lit dots, 0 = use the image's mean
+	BrailleColor     bool // Color each cell with the average of its lit pixels
+
+	// BlockGlyphs selects the glyph set for BLOCKS mode (depends on font coverage)
+	BlockGlyphs GlyphSet
 }
 
 // DefaultRenderOptions returns sensible default render options
@@ -61,11 +448,31 @@ func DefaultRenderOptions() RenderOptions {
 		Width:               0,           // Auto-detect
 		Height:              0,           // Auto-detect
 		Mode:                ASCII_COLOR, // Safe fallback
+		Fit:                 FIT_CONTAIN,
+		OriginRow:           0,
+		OriginColumn:        0,
+		Letterbox:           false,
+		LetterboxColor:      [3]uint8{0, 0, 0},
 		PaletteSize:         256,
+		TemporalPalette:     false,
+		DeltaFrames:         false,
+		Dither:              DITHER_NONE,
 		PreserveAspectRatio: true,
-		Contrast:            1.0,
-		Brightness:          0.0,
+		Adjustments:         DefaultAdjustments(),
 		TerminalAspectRatio: 0.5,
+		CellPixelWidth:      0,
+		CellPixelHeight:     0,
+		SixelMaxWidth:       0,
+		SixelMaxHeight:      0,
+		Passthrough:         MULTIPLEXER_NONE,
+		Background:          BACKGROUND_TERMINAL,
+		BackgroundColor:     [3]uint8{0, 0, 0},
+		TerminalBackground:  [3]uint8{0, 0, 0},
+		Ramp:                RAMP_SHORT.Characters(),
+		InvertRamp:          false,
+		BrailleThreshold:    0,
+		BrailleColor:        true,
+		BlockGlyphs:         QUADRANTS,
 	}
 }
 
@@ -89,6 +496,32 @@ type MediaInfo struct {
 	AudioCodec string
 	VideoCodec string
 	FrameCount int
+
+	// Metadata holds the EXIF information of photos, nil when there is none
+	Metadata *ImageMetadata
+}
+
+// ImageMetadata is the EXIF information of a photo
+type ImageMetadata struct {
+	CameraMake  string
+	CameraModel string
+	DateTaken   string // As recorded, "YYYY:MM:DD HH:MM:SS"
+	Orientation int    // EXIF orientation 1-8, 1 = stored upright (already applied to decoded images)
+
+	// Dimensions recorded by the camera, 0 when missing
+	PixelWidth  int
+	PixelHeight int
+
+	// HasGPS is set when the photo carries a location
+	HasGPS bool
+}
+
+// Camera returns the camera make and model, without the make repeated
+func (m ImageMetadata) Camera() string {
+	if m.CameraMake == "" || strings.HasPrefix(strings.ToLower(m.CameraModel), strings.ToLower(m.CameraMake)) {
+		return m.CameraModel
+	}
+	return strings.TrimSpace(m.CameraMake + " " + m.CameraModel)
 }
 
 // Frame represents a single frame of media content
@@ -110,9 +543,103 @@ type PlaybackStats struct {
 // TerminalCapabilities represents what the terminal supports
 type TerminalCapabilities struct {
 	SixelSupport   bool
+	KittyGraphics  bool
+	ITerm2Images   bool
 	TrueColor      bool
 	Color256       bool
 	Width          int
 	Height         int
 	UnicodeSupport bool
+
+	// LightBackground is set when the terminal appears to use a light theme
+	LightBackground bool
+
+	// Default colors of the terminal's theme; approximate unless ColorMethod
+	// is DETECTION_QUERY
+	ForegroundColor [3]uint8
+	BackgroundColor [3]uint8
+	ColorMethod     DetectionMethod
+
+	// Pixel size of one character cell, 0 when the terminal didn't report it
+	CellWidth      int
+	CellHeight     int
+	CellSizeMethod DetectionMethod
+
+	// SIXEL limits reported by XTSMGRAPHICS, 0 when unknown
+	SixelColorRegisters int
+	SixelMaxWidth       int
+	SixelMaxHeight      int
+
+	// Multiplexer running between TerminalTube and the terminal
+	Multiplexer Multiplexer
+
+	// Passthrough is the multiplexer graphics output and queries are wrapped
+	// for: Multiplexer, or MULTIPLEXER_NONE when tmux has passthrough disabled
+	Passthrough Multiplexer
+
+	// SynchronizedOutput is set when the terminal supports DEC private mode
+	// 2026, which holds back painting until a frame is complete
+	SynchronizedOutput bool
+
+	// How each graphics protocol's support was determined
+	SixelMethod  DetectionMethod
+	KittyMethod  DetectionMethod
+	ITerm2Method DetectionMethod
+}
+
+// Multiplexer identifies a terminal multiplexer between TerminalTube and the
+// real terminal
+type Multiplexer int
+
+const (
+	// MULTIPLEXER_NONE means output goes straight to the terminal
+	MULTIPLEXER_NONE Multiplexer = iota
+	// MULTIPLEXER_TMUX is tmux, which forwards DCS tmux; passthrough sequences
+	MULTIPLEXER_TMUX
+	// MULTIPLEXER_SCREEN is GNU screen, which forwards short DCS strings
+	MULTIPLEXER_SCREEN
+)
+
+// String returns the string representation of the multiplexer
+func (m Multiplexer) String() string {
+	switch m {
+	case MULTIPLEXER_NONE:
+		return "NONE"
+	case MULTIPLEXER_TMUX:
+		return "TMUX"
+	case MULTIPLEXER_SCREEN:
+		return "SCREEN"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// DetectionMethod records how a terminal capability was determined
+type DetectionMethod int
+
+const (
+	// DETECTION_NONE means nothing was found and a default is in use
+	DETECTION_NONE DetectionMethod = iota
+	// DETECTION_ENVIRONMENT means the result was guessed from environment variables
+	DETECTION_ENVIRONMENT
+	// DETECTION_QUERY means the terminal answered an escape sequence query
+	DETECTION_QUERY
+	// DETECTION_IOCTL means the result came from the terminal driver
+	DETECTION_IOCTL
+)
+
+// String returns the string representation of the detection method
+func (d DetectionMethod) String() string {
+	switch d {
+	case DETECTION_NONE:
+		return "NONE"
+	case DETECTION_ENVIRONMENT:
+		return "ENVIRONMENT"
+	case DETECTION_QUERY:
+		return "QUERY"
+	case DETECTION_IOCTL:
+		return "IOCTL"
+	default:
+		return "UNKNOWN"
+	}
 }
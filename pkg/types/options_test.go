@@ -0,0 +1,36 @@
+package types
+
+import "testing"
+
+func TestParseFitMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    FitMode
+		wantErr bool
+	}{
+		{"contain", FIT_CONTAIN, false},
+		{"COVER", FIT_COVER, false},
+		{"stretch", FIT_STRETCH, false},
+		{"original", FIT_ORIGINAL, false},
+		{"fit-width", FIT_WIDTH, false},
+		{"fit_width", FIT_WIDTH, false},
+		{"width", FIT_WIDTH, false},
+		{"fit", 0, true},
+		{"", 0, true},
+		{"unknown", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFitMode(tt.name)
+		if (err != nil) != tt.wantErr || (err == nil && got != tt.want) {
+			t.Errorf("ParseFitMode(%q) = %s, %v; want %s, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	// Every mode's name parses back to it
+	for mode := FIT_CONTAIN; mode <= FIT_WIDTH; mode++ {
+		if got, err := ParseFitMode(mode.String()); err != nil || got != mode {
+			t.Errorf("ParseFitMode(%q) = %s, %v", mode.String(), got, err)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"terminaltube/internal/terminal"
+	"terminaltube/pkg/types"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Keys controlling GIF and video playback
+const (
+	gifKeyHelp   = "Controls: space pause, left/right step a frame, f stats, ? keys, q/Esc stop"
+	videoKeyHelp = "Controls: space pause, left/right seek 5s, up/down volume, m mute, f stats, ? keys, q/Esc stop"
+)
+
+// adjustmentKeyHelp lists the keys that change adjustments during playback
+const adjustmentKeyHelp = "Adjust: b/B brightness, c/C contrast, g/G gamma, s/S saturation, u/U hue, e/E sharpen, i invert, n reset"
+
+// statusMessageDuration is how long a status message stays up after the last
+// change
+const statusMessageDuration = 2 * time.Second
+
+// Seek and volume steps for the video playback keys
+const (
+	seekStep   = 5.0 // Seconds
+	volumeStep = 0.1
+)
+
+// interrupted is closed when SIGINT or SIGTERM arrives, stopping playback and
+// the menu loop so the terminal is restored on the way out
+var interrupted = make(chan struct{})
+
+// isStopKey reports whether a key ends playback
+func isStopKey(key terminal.KeyEvent) bool {
+	return key.Key == terminal.KeyCtrlC || key.Key == terminal.KeyEscape ||
+		(key.Key == terminal.KeyRune && key.Rune == 'q')
+}
+
+// formatPlaybackTime formats seconds as m:ss
+func formatPlaybackTime(seconds float64) string {
+	total := int(max(0, seconds))
+	return fmt.Sprintf("%d:%02d", total/60, total%60)
+}
+
+// updatePlaybackStats recomputes the frame and drop rates from the counts
+func updatePlaybackStats(stats *types.PlaybackStats) {
+	elapsed := float64(time.Now().UnixNano()-stats.StartTime) / 1000000000.0
+	if elapsed > 0 {
+		stats.FPS = float64(stats.FramesRendered) / elapsed
+	}
+	if stats.FramesRendered+stats.FramesDropped > 0 {
+		stats.DropRate = float64(stats.FramesDropped) / float64(stats.FramesRendered+stats.FramesDropped) * 100.0
+	}
+}
+
+// drawPlaybackStats shows the playback statistics on the given row, followed
+// by extra if it isn't empty, cut to width columns
+func drawPlaybackStats(termControl *terminal.Control, stats *types.PlaybackStats, options types.RenderOptions, row, width int, extra string) {
+	// SIXEL cursor positioning is tricky, and its output may cover the row
+	if options.Mode == types.SIXEL {
+		return
+	}
+
+	text := fmt.Sprintf("FPS: %.1f | Frames: %d | Dropped: %d (%.1f%%) | Size: %dx%d",
+		stats.FPS, stats.FramesRendered, stats.FramesDropped, stats.DropRate, options.Width, options.Height)
+	if extra != "" {
+		text += " | " + extra
+	}
+	termControl.MoveCursor(row, 1)
+	termControl.WriteString(fitWidth(text, width))
+}
+
+// statusOverlay shows adjustment and playback changes on the bottom row for a
+// moment after each change, and a lasting status such as "Paused" otherwise
+type statusOverlay struct {
+	message      string // Shown until visibleUntil
+	visibleUntil time.Time
+	status       string // Shown while no message is
+	shown        bool
+}
+
+// flash shows message for statusMessageDuration
+func (o *statusOverlay) flash(message string) {
+	o.message = message
+	o.visibleUntil = time.Now().Add(statusMessageDuration)
+}
+
+// setStatus sets the text shown when no message is; empty shows nothing
+func (o *statusOverlay) setStatus(status string) {
+	o.status = status
+}
+
+// expiry returns a channel that fires when the current message expires, or
+// nil if no message is showing
+func (o *statusOverlay) expiry() <-chan time.Time {
+	remaining := time.Until(o.visibleUntil)
+	if remaining <= 0 {
+		return nil
+	}
+	return time.After(remaining)
+}
+
+// handleKey changes adj according to an adjustment key, reporting whether the
+// key was one
+func (o *statusOverlay) handleKey(adj *types.Adjustments, key terminal.KeyEvent) bool {
+	if key.Key != terminal.KeyRune {
+		return false
+	}
+
+	switch key.Rune {
+	case 'b':
+		adj.Brightness -= 0.05
+	case 'B':
+		adj.Brightness += 0.05
+	case 'c':
+		adj.Contrast -= 0.1
+	case 'C':
+		adj.Contrast += 0.1
+	case 'g':
+		adj.Gamma -= 0.1
+	case 'G':
+		adj.Gamma += 0.1
+	case 's':
+		adj.Saturation -= 0.1
+	case 'S':
+		adj.Saturation += 0.1
+	case 'u':
+		adj.HueShift -= 15
+	case 'U':
+		adj.HueShift += 15
+	case 'e':
+		adj.Sharpen -= 0.25
+	case 'E':
+		adj.Sharpen += 0.25
+	case 'i':
+		adj.Invert = !adj.Invert
+	case 'n':
+		*adj = types.DefaultAdjustments()
+	default:
+		return false
+	}
+
+	*adj = adj.Clamped()
+	o.flash(adjustmentStatus(*adj, key.Rune))
+	return true
+}
+
+// adjustmentStatus describes the adjustment an adjustment key changed
+func adjustmentStatus(adj types.Adjustments, key rune) string {
+	switch unicode.ToLower(key) {
+	case 'b':
+		return fmt.Sprintf("Brightness %+.2f", adj.Brightness)
+	case 'c':
+		return fmt.Sprintf("Contrast %.1f", adj.Contrast)
+	case 'g':
+		return fmt.Sprintf("Gamma %.1f", adj.Gamma)
+	case 's':
+		return fmt.Sprintf("Saturation %.1f", adj.Saturation)
+	case 'u':
+		return fmt.Sprintf("Hue %.0f°", adj.HueShift)
+	case 'e':
+		return fmt.Sprintf("Sharpen %.2f", adj.Sharpen)
+	case 'i':
+		if adj.Invert {
+			return "Invert on"
+		}
+		return "Invert off"
+	default:
+		return "Adjustments reset"
+	}
+}
+
+// fitWidth cuts text to at most width columns, counting one per rune, so a
+// line written on the bottom row never wraps and scrolls the screen
+func fitWidth(text string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(text) <= width {
+		return text
+	}
+	return string([]rune(text)[:width])
+}
+
+// draw shows the current message or status on the given row, cut to width
+// columns, and clears the row once there is neither
+func (o *statusOverlay) draw(termControl *terminal.Control, options types.RenderOptions, row, width int) {
+	// SIXEL output may cover the bottom row, as with the playback stats
+	if options.Mode == types.SIXEL {
+		return
+	}
+
+	text := o.status
+	if time.Now().Before(o.visibleUntil) {
+		text = o.message
+	}
+
+	if text != "" {
+		termControl.MoveCursor(row, 1)
+		termControl.WriteString("\033[2K" + fitWidth(text, width))
+		o.shown = true
+	} else if o.shown {
+		termControl.MoveCursor(row, 1)
+		termControl.WriteString("\033[2K")
+		o.shown = false
+	}
+}
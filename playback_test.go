@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"terminaltube/internal/terminal"
+	"terminaltube/pkg/types"
+	"testing"
+	"unicode/utf8"
+)
+
+// csiPattern matches the cursor and erase sequences the overlay writes
+var csiPattern = regexp.MustCompile("\033\\[[0-9;]*[A-Za-z]")
+
+func TestFitWidth(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		want  string
+	}{
+		{"Gamma 1.2", 20, "Gamma 1.2"},
+		{"Gamma 1.2", 9, "Gamma 1.2"},
+		{"Gamma 1.2", 5, "Gamma"},
+		{"Hue 15°", 7, "Hue 15°"},
+		{"Hue 15°°", 7, "Hue 15°"},
+		{"Paused", 0, ""},
+	}
+
+	for _, tt := range tests {
+		if got := fitWidth(tt.text, tt.width); got != tt.want {
+			t.Errorf("fitWidth(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestStatusOverlayFitsWidth(t *testing.T) {
+	messages := []string{gifKeyHelp, videoKeyHelp, adjustmentKeyHelp, "Hue 345°", "Paused"}
+	options := types.RenderOptions{Mode: types.EXACT}
+
+	for _, message := range messages {
+		for _, width := range []int{1, 10, 40, 80, 200} {
+			var out bytes.Buffer
+			termControl := terminal.NewControlWriter(&out)
+
+			var overlay statusOverlay
+			overlay.flash(message)
+			overlay.draw(termControl, options, 24, width)
+			termControl.Flush()
+
+			if !strings.HasPrefix(out.String(), "\033[24;1H\033[2K") {
+				t.Fatalf("draw wrote %q, want it to start by clearing row 24", out.String())
+			}
+			text := csiPattern.ReplaceAllString(out.String(), "")
+			if cells := utf8.RuneCountInString(text); cells > width || cells != min(width, utf8.RuneCountInString(message)) {
+				t.Errorf("draw(%q) at width %d wrote %d cells: %q", message, width, cells, text)
+			}
+		}
+	}
+}
+
+func TestAdjustmentStatus(t *testing.T) {
+	tests := []struct {
+		key  rune
+		want string
+	}{
+		{'G', "Gamma 1.1"},
+		{'b', "Brightness -0.05"},
+		{'U', "Hue 15°"},
+		{'i', "Invert on"},
+		{'n', "Adjustments reset"},
+	}
+
+	for _, tt := range tests {
+		var overlay statusOverlay
+		adj := types.DefaultAdjustments()
+		key := terminal.KeyEvent{Key: terminal.KeyRune, Rune: tt.key}
+		if !overlay.handleKey(&adj, key) {
+			t.Fatalf("handleKey(%q) didn't take the key", tt.key)
+		}
+		if overlay.message != tt.want {
+			t.Errorf("key %q flashed %q, want %q", tt.key, overlay.message, tt.want)
+		}
+	}
+}
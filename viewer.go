@@ -0,0 +1,256 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"path/filepath"
+	"strings"
+	"terminaltube/internal/decoder"
+	"terminaltube/internal/renderer"
+	"terminaltube/internal/terminal"
+	"terminaltube/pkg/types"
+	"unicode/utf8"
+)
+
+// Zoom limits and steps for the image viewer
+const (
+	viewerMinZoom  = 0.25
+	viewerMaxZoom  = 32.0
+	viewerZoomStep = 1.25
+
+	// viewerPanStep is how far one pan key moves, as a fraction of the view
+	viewerPanStep = 0.2
+)
+
+// viewerKeyHelp lists the image viewer keys, shown in place of the status
+// while ? is toggled on
+const viewerKeyHelp = "arrows/hjkl pan, +/- zoom, r/R rotate, f flip, 0 reset, i info, ? keys, q quit"
+
+// imageView is the zoom, pan and orientation of the image viewer
+type imageView struct {
+	zoom             float64 // 1 = the fit mode's size
+	centerX, centerY float64 // View center, as fractions of the oriented image
+	quarterTurns     int     // Clockwise rotation in 90° steps
+	flipped          bool    // Mirrored left to right after rotating
+}
+
+// newImageView returns the view showing the whole image as the fit mode places it
+func newImageView() imageView {
+	return imageView{zoom: 1, centerX: 0.5, centerY: 0.5}
+}
+
+// handleKey updates the view for a viewer key, reporting whether it changed
+// the view
+func (v *imageView) handleKey(key terminal.KeyEvent, layout frameLayout) bool {
+	// Pan by a share of what is visible, so steps feel the same at any zoom
+	panX := layout.cropWidth * viewerPanStep
+	panY := layout.cropHeight * viewerPanStep
+
+	switch {
+	case key.Key == terminal.KeyLeft || key.Rune == 'h':
+		v.centerX -= panX
+	case key.Key == terminal.KeyRight || key.Rune == 'l':
+		v.centerX += panX
+	case key.Key == terminal.KeyUp || key.Rune == 'k':
+		v.centerY -= panY
+	case key.Key == terminal.KeyDown || key.Rune == 'j':
+		v.centerY += panY
+	case key.Rune == '+' || key.Rune == '=':
+		v.zoom = math.Min(viewerMaxZoom, v.zoom*viewerZoomStep)
+	case key.Rune == '-' || key.Rune == '_':
+		v.zoom = math.Max(viewerMinZoom, v.zoom/viewerZoomStep)
+	case key.Rune == 'r':
+		// The center turns with the image
+		v.rotate(1)
+		v.centerX, v.centerY = 1-v.centerY, v.centerX
+	case key.Rune == 'R':
+		v.rotate(-1)
+		v.centerX, v.centerY = v.centerY, 1-v.centerX
+	case key.Rune == 'f':
+		v.flipped = !v.flipped
+		v.centerX = 1 - v.centerX
+	case key.Rune == '0':
+		*v = newImageView()
+	default:
+		return false
+	}
+	return true
+}
+
+// rotate turns the view clockwise by quarter turns as seen on screen
+// The rotation is applied before the flip, so a flipped view turns the
+// other way round to look the same.
+func (v *imageView) rotate(quarterTurns int) {
+	if v.flipped {
+		quarterTurns = -quarterTurns
+	}
+	v.quarterTurns = (v.quarterTurns + quarterTurns + 4) % 4
+}
+
+// layout places the oriented image at the view's zoom and center
+// The center is pulled back inside the image so panning stops at the edges.
+func (v *imageView) layout(imgWidth, imgHeight, termWidth, termHeight int, options types.RenderOptions) frameLayout {
+	width, height := pictureSize(imgWidth, imgHeight, termWidth, termHeight, options)
+	layout := placeFrame(width*v.zoom, height*v.zoom, v.centerX, v.centerY, termWidth, termHeight, options)
+
+	v.centerX = layout.cropX + layout.cropWidth/2
+	v.centerY = layout.cropY + layout.cropHeight/2
+	return layout
+}
+
+// status describes the view for the viewer's bottom row
+func (v *imageView) status() string {
+	flip := ""
+	if v.flipped {
+		flip = " | flipped"
+	}
+	return fmt.Sprintf("Zoom %.0f%% | Rotation %d°%s | Center %.0f%%,%.0f%% | ? keys",
+		v.zoom*100, v.quarterTurns*90, flip, v.centerX*100, v.centerY*100)
+}
+
+// imageInfoLines describes an image and its EXIF metadata for the info panel
+func imageInfoLines(imagePath string, info *types.MediaInfo) []string {
+	lines := []string{
+		"File: " + filepath.Base(imagePath),
+		"Format: " + strings.ToUpper(info.VideoCodec),
+		fmt.Sprintf("Dimensions: %dx%d", info.Width, info.Height),
+	}
+
+	metadata := info.Metadata
+	if metadata == nil {
+		return append(lines, "No EXIF metadata")
+	}
+
+	if camera := metadata.Camera(); camera != "" {
+		lines = append(lines, "Camera: "+camera)
+	}
+	if metadata.DateTaken != "" {
+		lines = append(lines, "Taken: "+metadata.DateTaken)
+	}
+	if metadata.PixelWidth > 0 && metadata.PixelHeight > 0 {
+		lines = append(lines, fmt.Sprintf("Recorded size: %dx%d", metadata.PixelWidth, metadata.PixelHeight))
+	}
+	if metadata.Orientation != 1 {
+		lines = append(lines, fmt.Sprintf("Orientation: %d (corrected)", metadata.Orientation))
+	}
+	if metadata.HasGPS {
+		lines = append(lines, "GPS: location recorded")
+	} else {
+		lines = append(lines, "GPS: none")
+	}
+	return lines
+}
+
+// drawInfoPanel draws a box with the given lines in the top-left corner
+func drawInfoPanel(termControl *terminal.Control, lines []string, unicode bool) {
+	horizontal, vertical := "-", "|"
+	corners := [4]string{"+", "+", "+", "+"}
+	if unicode {
+		horizontal, vertical = "─", "│"
+		corners = [4]string{"┌", "┐", "└", "┘"}
+	}
+
+	width := 0
+	for _, line := range lines {
+		width = max(width, utf8.RuneCountInString(line))
+	}
+
+	termControl.WriteString("\033[0m")
+	termControl.MoveCursor(2, 3)
+	termControl.WriteString(corners[0] + strings.Repeat(horizontal, width+2) + corners[1])
+	for i, line := range lines {
+		termControl.MoveCursor(3+i, 3)
+		padding := strings.Repeat(" ", width-utf8.RuneCountInString(line))
+		termControl.WriteString(vertical + " " + line + padding + " " + vertical)
+	}
+	termControl.MoveCursor(3+len(lines), 3)
+	termControl.WriteString(corners[2] + strings.Repeat(horizontal, width+2) + corners[3])
+}
+
+// viewImage shows an image interactively until the user quits, re-rendering
+// on every change and whenever the terminal is resized
+func viewImage(img image.Image, info []string, bestRenderer renderer.Renderer, termControl *terminal.Control, capabilities types.TerminalCapabilities, options types.RenderOptions, keys <-chan terminal.KeyEvent) {
+	view := newImageView()
+	showInfo := false
+	showHelp := false
+	oriented := img
+	orientedTurns, orientedFlip := 0, false
+
+	// Text renderers only repaint what changed while panning
+	options.DeltaFrames = true
+
+	resized, stopResize := terminal.WatchResize()
+	defer stopResize()
+
+	var shown frameLayout
+	redraw, repaint := true, false
+	for {
+		if redraw {
+			if view.quarterTurns != orientedTurns || view.flipped != orientedFlip {
+				oriented = decoder.TransformImage(img, view.quarterTurns, view.flipped)
+				orientedTurns, orientedFlip = view.quarterTurns, view.flipped
+			}
+
+			termControl.BeginFrame()
+
+			bounds := oriented.Bounds()
+			layout := view.layout(bounds.Dx(), bounds.Dy(), capabilities.Width, capabilities.Height, options)
+			layout.apply(&options)
+
+			// Repaint the letterbox whenever the frame moves or changes size,
+			// and everything once the info panel is closed
+			if layout.width != shown.width || layout.height != shown.height ||
+				layout.column != shown.column || layout.row != shown.row || repaint {
+				clearScreen(termControl, options)
+				resetFrameTracking(bestRenderer)
+			}
+			shown = layout
+
+			rendered, err := bestRenderer.Render(layout.crop(oriented), options)
+			if err != nil {
+				termControl.MoveCursor(capabilities.Height, 1)
+				termControl.WriteString("\033[2K" + fitWidth(fmt.Sprintf("Failed to render image: %v", err), capabilities.Width))
+			} else {
+				termControl.MoveCursorHome()
+				termControl.WriteString(rendered)
+				if showInfo {
+					drawInfoPanel(termControl, info, capabilities.UnicodeSupport)
+				}
+				status := view.status()
+				if showHelp {
+					status = viewerKeyHelp
+				}
+				termControl.MoveCursor(capabilities.Height, 1)
+				termControl.WriteString("\033[2K" + fitWidth(status, capabilities.Width))
+			}
+			termControl.EndFrame()
+			redraw, repaint = false, false
+		}
+
+		select {
+		case key := <-keys:
+			switch {
+			case key.Key == terminal.KeyCtrlC || key.Key == terminal.KeyEscape ||
+				key.Key == terminal.KeyEnter || key.Rune == 'q':
+				return
+			case key.Rune == 'i':
+				showInfo = !showInfo
+				redraw, repaint = true, !showInfo
+			case key.Rune == '?':
+				showHelp = !showHelp
+				redraw = true
+			case view.handleKey(key, shown):
+				redraw = true
+			}
+		case <-resized:
+			// The frame may keep its size and position, but whatever was
+			// outside the old screen area has to be repainted
+			if refreshTerminalSize(&capabilities, &options) {
+				redraw, repaint = true, true
+			}
+		case <-interrupted:
+			return
+		}
+	}
+}